@@ -0,0 +1,140 @@
+// Package bus implements a small publish/subscribe bus on top of Hyprland's
+// custom events, allowing independent programs to coordinate through the
+// compositor without a separate IPC mechanism.
+//
+// Messages are published with the 'event' dispatcher (see
+// hyprland.RequestClient.CustomEvent) as a JSON envelope containing the topic
+// and the payload, and received from the event socket as event.Custom.
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/event"
+)
+
+var (
+	// Returned when the topic is empty.
+	ErrEmptyTopic = errors.New("empty topic")
+	// Returned when a custom event is not a message published by this
+	// package.
+	ErrInvalidMessage = errors.New("invalid message")
+)
+
+// Message is a message published in the bus.
+type Message struct {
+	Topic   string          `json:"topic"`
+	Payload json.RawMessage `json:"payload"`
+}
+
+// Decode unmarshals the message payload in v.
+func (m Message) Decode(v any) error {
+	if err := json.Unmarshal(m.Payload, v); err != nil {
+		return fmt.Errorf("error while unmarshal payload: %w", err)
+	}
+
+	return nil
+}
+
+// Bus publishes and receives messages using Hyprland's custom events.
+type Bus struct {
+	client *hyprland.RequestClient
+	events *event.EventClient
+}
+
+// Initiate a new bus.
+// The client is used to publish messages and the events client to receive
+// them, either can be nil if the bus is only used to subscribe or publish,
+// respectively.
+func New(client *hyprland.RequestClient, events *event.EventClient) *Bus {
+	return &Bus{client: client, events: events}
+}
+
+// Encode a message for a topic, returning the data that can be passed to
+// hyprland.RequestClient.CustomEvent. The payload is marshalled as JSON.
+func Encode(topic string, payload any) (string, error) {
+	if topic == "" {
+		return "", ErrEmptyTopic
+	}
+
+	p, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("error while marshal payload: %w", err)
+	}
+
+	// Compact JSON never includes new lines, so the message will be
+	// received as a single event
+	data, err := json.Marshal(Message{Topic: topic, Payload: p})
+	if err != nil {
+		return "", fmt.Errorf("error while marshal message: %w", err)
+	}
+
+	return string(data), nil
+}
+
+// Decode a custom event in a message. Returns an error wrapping
+// [ErrInvalidMessage] if the event was not published by this package, e.g.:
+// it was emitted by another program using the 'event' dispatcher.
+func Decode(c event.Custom) (m Message, err error) {
+	if err := json.Unmarshal([]byte(c), &m); err != nil {
+		return m, fmt.Errorf("%w: %w", ErrInvalidMessage, err)
+	}
+
+	if m.Topic == "" {
+		return m, fmt.Errorf("%w: %w", ErrInvalidMessage, ErrEmptyTopic)
+	}
+
+	return m, nil
+}
+
+// Publish a message with the payload marshalled as JSON to a topic.
+func (b *Bus) Publish(ctx context.Context, topic string, payload any) error {
+	data, err := Encode(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.client.CustomEventWithContext(ctx, data); err != nil {
+		return fmt.Errorf("error while publishing message: %w", err)
+	}
+
+	return nil
+}
+
+// Subscribe returns a channel that receives all messages published in the
+// topics passed as parameters, or in all topics if none is passed. Custom
+// events not published by this package are ignored.
+// The channel is closed once the context is done or an error happens, in
+// this case event.EventClient.Err returns the error.
+// Uses event.EventClient.Events, so the same restrictions apply.
+func (b *Bus) Subscribe(ctx context.Context, topics ...string) <-chan Message {
+	ch := make(chan Message)
+
+	go func() {
+		defer close(ch)
+
+		for e := range b.events.Events(ctx, event.EventCustom) {
+			m, err := Decode(e.(event.Custom))
+			if err != nil {
+				continue
+			}
+
+			if len(topics) > 0 && !slices.Contains(topics, m.Topic) {
+				continue
+			}
+
+			select {
+			case ch <- m:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch
+}
@@ -0,0 +1,90 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/event"
+	"github.com/thiagokokada/hyprland-go/hyprlandtest"
+	"github.com/thiagokokada/hyprland-go/internal/assert"
+)
+
+type payload struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestEncodeDecode(t *testing.T) {
+	data, err := Encode("foo", payload{Name: "bar, baz;\nqux", Count: 1})
+	assert.NoError(t, err)
+	assert.False(t, strings.Contains(data, "\n"))
+
+	m, err := Decode(event.Custom(data))
+	assert.NoError(t, err)
+	assert.Equal(t, m.Topic, "foo")
+
+	var p payload
+	assert.NoError(t, m.Decode(&p))
+	assert.Equal(t, p, payload{Name: "bar, baz;\nqux", Count: 1})
+
+	_, err = Encode("", p)
+	assert.True(t, errors.Is(err, ErrEmptyTopic))
+
+	for _, c := range []event.Custom{"", "foo", `{"payload": 1}`} {
+		_, err = Decode(c)
+		assert.True(t, errors.Is(err, ErrInvalidMessage))
+	}
+}
+
+func TestPublishSubscribe(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	es := s.Instance.NewEventServer(t)
+
+	ev, err := event.NewClient(es.Socket)
+	assert.NoError(t, err)
+
+	defer ev.Close()
+
+	conn := es.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	s.HandleResponse("version", `{"tag": "v0.47.2"}`)
+	// Forward the event dispatcher to the event socket, like Hyprland does
+	s.Handle("dispatch", func(cmd hyprlandtest.Command) string {
+		data, _ := strings.CutPrefix(cmd.Args, "event ")
+		if err := conn.Emit("custom", data); err != nil {
+			return err.Error()
+		}
+
+		return "ok"
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	b := New(hyprland.NewClient(s.Socket), ev)
+	ch := b.Subscribe(ctx, "foo")
+
+	assert.NoError(t, conn.Emit("custom", "not from the bus"))
+	assert.NoError(t, b.Publish(ctx, "bar", payload{Name: "ignored"}))
+	assert.NoError(t, b.Publish(ctx, "foo", payload{Name: "a, b", Count: 2}))
+
+	m := <-ch
+	assert.Equal(t, m.Topic, "foo")
+
+	var p payload
+	assert.NoError(t, m.Decode(&p))
+	assert.Equal(t, p, payload{Name: "a, b", Count: 2})
+
+	cancel()
+
+	for range ch {
+		t.Error("unexpected message")
+	}
+}
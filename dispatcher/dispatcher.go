@@ -0,0 +1,418 @@
+// Package dispatcher implements typed versions of Hyprland's dispatchers,
+// that can be passed to hyprland.RequestClient.DispatchCommands.
+//
+// Arguments that receive a window accept an empty string to mean the active
+// window, similar to how Hyprland handles optional window arguments.
+package dispatcher
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Returned when a [WindowSelector] is invalid.
+var ErrInvalidSelector = errors.New("invalid window selector")
+
+// Strings serialises a list of dispatchers, the result can be passed to
+// hyprland.RequestClient.Dispatch.
+func Strings(ds ...Dispatcher) []string {
+	params := make([]string, 0, len(ds))
+	for _, d := range ds {
+		params = append(params, d.String())
+	}
+
+	return params
+}
+
+// Raw dispatcher, for dispatchers not yet supported by this package, e.g.:
+// Raw("hyprexpo:expo", "toggle").
+func Raw(name string, arg string) Dispatcher {
+	return command{name, arg}
+}
+
+// Appends an optional window to the argument list.
+func withWindow(arg string, window WindowSelector) string {
+	if window == "" {
+		return arg
+	}
+
+	if arg == "" {
+		return string(window)
+	}
+
+	return arg + "," + string(window)
+}
+
+// Exec executes a shell command, e.g.: "exec kitty".
+func Exec(cmd string) Dispatcher {
+	return command{"exec", cmd}
+}
+
+// ExecWithRules executes a shell command with window rules applied to the
+// spawned window, e.g.: "exec [workspace 2 silent;float] kitty".
+func ExecWithRules(rules []string, cmd string) Dispatcher {
+	return command{"exec", "[" + strings.Join(rules, ";") + "] " + cmd}
+}
+
+// ExecRaw executes a raw shell command (does not support rules), e.g.:
+// "execr kitty".
+func ExecRaw(cmd string) Dispatcher {
+	return command{"execr", cmd}
+}
+
+// Pass passes the key (with mods) to a specified window.
+func Pass(window WindowSelector) Dispatcher {
+	return command{"pass", string(window)}
+}
+
+// SendShortcut sends specified keys (with mods) to an optionally specified
+// window, e.g.: "sendshortcut SUPER, F, class:kitty".
+func SendShortcut(mod string, key string, window WindowSelector) Dispatcher {
+	arg := mod + ", " + key
+	if window != "" {
+		arg += ", " + string(window)
+	}
+
+	return command{"sendshortcut", arg}
+}
+
+// KillActive closes (not kills) the active window.
+func KillActive() Dispatcher {
+	return command{"killactive", ""}
+}
+
+// ForceKillActive kills the active window.
+func ForceKillActive() Dispatcher {
+	return command{"forcekillactive", ""}
+}
+
+// CloseWindow closes a specified window.
+func CloseWindow(window WindowSelector) Dispatcher {
+	return command{"closewindow", string(window)}
+}
+
+// KillWindow kills a specified window.
+func KillWindow(window WindowSelector) Dispatcher {
+	return command{"killwindow", string(window)}
+}
+
+// Workspace changes the workspace.
+func Workspace(ws WorkspaceSelector) Dispatcher {
+	return command{"workspace", string(ws)}
+}
+
+// MoveToWorkspace moves a window (or the active window if empty) to a
+// workspace.
+func MoveToWorkspace(ws WorkspaceSelector, window WindowSelector) Dispatcher {
+	return command{"movetoworkspace", withWindow(string(ws), window)}
+}
+
+// MoveToWorkspaceSilent is the same as [MoveToWorkspace], but does not switch
+// to the workspace.
+func MoveToWorkspaceSilent(ws WorkspaceSelector, window WindowSelector) Dispatcher {
+	return command{"movetoworkspacesilent", withWindow(string(ws), window)}
+}
+
+// ToggleFloating toggles a window (or the active window if empty) between
+// floating and tiled.
+func ToggleFloating(window WindowSelector) Dispatcher {
+	return command{"togglefloating", string(window)}
+}
+
+// SetFloating sets a window (or the active window if empty) to floating.
+func SetFloating(window WindowSelector) Dispatcher {
+	return command{"setfloating", string(window)}
+}
+
+// SetTiled sets a window (or the active window if empty) to tiled.
+func SetTiled(window WindowSelector) Dispatcher {
+	return command{"settiled", string(window)}
+}
+
+// Fullscreen toggles the fullscreen mode of the active window.
+func Fullscreen(mode FullscreenMode) Dispatcher {
+	return command{"fullscreen", strconv.Itoa(int(mode))}
+}
+
+// DPMS sets all monitors (or the monitor if not empty) DPMS status.
+func DPMS(state State, monitor MonitorSelector) Dispatcher {
+	arg := string(state)
+	if monitor != "" {
+		arg += " " + string(monitor)
+	}
+
+	return command{"dpms", arg}
+}
+
+// Pin pins a window (or the active window if empty), i.e. show it on all
+// workspaces. Only works for floating windows.
+func Pin(window WindowSelector) Dispatcher {
+	return command{"pin", string(window)}
+}
+
+// MoveFocus moves the focus in a direction.
+func MoveFocus(d Direction) Dispatcher {
+	return command{"movefocus", string(d)}
+}
+
+// MoveWindow moves the active window in a direction.
+func MoveWindow(d Direction) Dispatcher {
+	return command{"movewindow", string(d)}
+}
+
+// MoveWindowToMonitor moves the active window to a monitor.
+func MoveWindowToMonitor(monitor MonitorSelector) Dispatcher {
+	return command{"movewindow", "mon:" + string(monitor)}
+}
+
+// SwapWindow swaps the active window with another window in a direction.
+func SwapWindow(d Direction) Dispatcher {
+	return command{"swapwindow", string(d)}
+}
+
+// CenterWindow centers the active window. Only works for floating windows.
+// If respectReserved is true, it will respect the monitor reserved area.
+func CenterWindow(respectReserved bool) Dispatcher {
+	if respectReserved {
+		return command{"centerwindow", "1"}
+	}
+
+	return command{"centerwindow", ""}
+}
+
+// ResizeActive resizes the active window relative to its current size.
+func ResizeActive(x, y int) Dispatcher {
+	return command{"resizeactive", fmt.Sprintf("%d %d", x, y)}
+}
+
+// ResizeActiveExact resizes the active window to an exact size.
+func ResizeActiveExact(w, h int) Dispatcher {
+	return command{"resizeactive", fmt.Sprintf("exact %d %d", w, h)}
+}
+
+// MoveActive moves the active window relative to its current position.
+func MoveActive(x, y int) Dispatcher {
+	return command{"moveactive", fmt.Sprintf("%d %d", x, y)}
+}
+
+// MoveActiveExact moves the active window to an exact position.
+func MoveActiveExact(x, y int) Dispatcher {
+	return command{"moveactive", fmt.Sprintf("exact %d %d", x, y)}
+}
+
+// ResizeWindowPixel resizes a window relative to its current size.
+func ResizeWindowPixel(x, y int, window WindowSelector) Dispatcher {
+	return command{"resizewindowpixel", withWindow(fmt.Sprintf("%d %d", x, y), window)}
+}
+
+// MoveWindowPixel moves a window relative to its current position.
+func MoveWindowPixel(x, y int, window WindowSelector) Dispatcher {
+	return command{"movewindowpixel", withWindow(fmt.Sprintf("%d %d", x, y), window)}
+}
+
+// CycleNext focuses the next window in the workspace, or the previous one if
+// prev is true.
+func CycleNext(prev bool) Dispatcher {
+	if prev {
+		return command{"cyclenext", "prev"}
+	}
+
+	return command{"cyclenext", ""}
+}
+
+// SwapNext swaps the focused window with the next window in the workspace, or
+// the previous one if prev is true.
+func SwapNext(prev bool) Dispatcher {
+	if prev {
+		return command{"swapnext", "prev"}
+	}
+
+	return command{"swapnext", ""}
+}
+
+// FocusWindow focuses the first window matching.
+func FocusWindow(window WindowSelector) Dispatcher {
+	return command{"focuswindow", string(window)}
+}
+
+// FocusMonitor focuses a monitor.
+func FocusMonitor(monitor MonitorSelector) Dispatcher {
+	return command{"focusmonitor", string(monitor)}
+}
+
+// SplitRatio changes the split ratio relative to the current one, e.g.:
+// 0.1 or -0.1.
+func SplitRatio(ratio float64) Dispatcher {
+	return command{"splitratio", strconv.FormatFloat(ratio, 'f', -1, 64)}
+}
+
+// SplitRatioExact changes the split ratio to an exact value.
+func SplitRatioExact(ratio float64) Dispatcher {
+	return command{"splitratio", "exact " + strconv.FormatFloat(ratio, 'f', -1, 64)}
+}
+
+// ToggleOpaque toggles the active window to be always opaque.
+func ToggleOpaque() Dispatcher {
+	return command{"toggleopaque", ""}
+}
+
+// MoveCursorToCorner moves the cursor to the corner of the active window.
+// Corner is 0 (bottom left), 1 (bottom right), 2 (top right) or 3 (top
+// left).
+func MoveCursorToCorner(corner int) Dispatcher {
+	return command{"movecursortocorner", strconv.Itoa(corner)}
+}
+
+// MoveCursor moves the cursor to a specified position.
+func MoveCursor(x, y int) Dispatcher {
+	return command{"movecursor", fmt.Sprintf("%d %d", x, y)}
+}
+
+// RenameWorkspace renames a workspace.
+func RenameWorkspace(id int, name string) Dispatcher {
+	return command{"renameworkspace", fmt.Sprintf("%d %s", id, name)}
+}
+
+// Exit exits the compositor with no questions asked.
+func Exit() Dispatcher {
+	return command{"exit", ""}
+}
+
+// ForceRendererReload forces the renderer to reload all resources and outputs.
+func ForceRendererReload() Dispatcher {
+	return command{"forcerendererreload", ""}
+}
+
+// MoveCurrentWorkspaceToMonitor moves the active workspace to a monitor.
+func MoveCurrentWorkspaceToMonitor(monitor MonitorSelector) Dispatcher {
+	return command{"movecurrentworkspacetomonitor", string(monitor)}
+}
+
+// FocusWorkspaceOnCurrentMonitor focuses the requested workspace on the
+// current monitor, swapping the current workspace to a different monitor if
+// necessary.
+func FocusWorkspaceOnCurrentMonitor(ws WorkspaceSelector) Dispatcher {
+	return command{"focusworkspaceoncurrentmonitor", string(ws)}
+}
+
+// MoveWorkspaceToMonitor moves a workspace to a monitor.
+func MoveWorkspaceToMonitor(ws WorkspaceSelector, monitor MonitorSelector) Dispatcher {
+	return command{"moveworkspacetomonitor", string(ws) + " " + string(monitor)}
+}
+
+// SwapActiveWorkspaces swaps the active workspaces between two monitors.
+func SwapActiveWorkspaces(m1, m2 MonitorSelector) Dispatcher {
+	return command{"swapactiveworkspaces", string(m1) + " " + string(m2)}
+}
+
+// AlterZOrder modifies the window stack order of a window (or the active
+// window if empty). Only works for floating windows.
+func AlterZOrder(z ZOrder, window WindowSelector) Dispatcher {
+	return command{"alterzorder", withWindow(string(z), window)}
+}
+
+// ToggleSpecialWorkspace toggles a special workspace on/off. If name is empty,
+// the default special workspace is used.
+func ToggleSpecialWorkspace(name string) Dispatcher {
+	return command{"togglespecialworkspace", name}
+}
+
+// FocusUrgentOrLast focuses the urgent window or the last window.
+func FocusUrgentOrLast() Dispatcher {
+	return command{"focusurgentorlast", ""}
+}
+
+// FocusCurrentOrLast switches focus from current to previously focused window.
+func FocusCurrentOrLast() Dispatcher {
+	return command{"focuscurrentorlast", ""}
+}
+
+// ToggleGroup toggles the current active window into a group.
+func ToggleGroup() Dispatcher {
+	return command{"togglegroup", ""}
+}
+
+// ChangeGroupActive switches to the next window in a group.
+func ChangeGroupActive(d GroupDirection) Dispatcher {
+	return command{"changegroupactive", string(d)}
+}
+
+// ChangeGroupActiveIndex switches to the window at index (starting at 1) in a
+// group.
+func ChangeGroupActiveIndex(index int) Dispatcher {
+	return command{"changegroupactive", strconv.Itoa(index)}
+}
+
+// LockGroups locks the groups (all groups will not accept new windows).
+func LockGroups(state LockState) Dispatcher {
+	return command{"lockgroups", string(state)}
+}
+
+// LockActiveGroup locks the current group (the current group will not accept
+// new windows or be moved to other groups).
+func LockActiveGroup(state LockState) Dispatcher {
+	return command{"lockactivegroup", string(state)}
+}
+
+// MoveIntoGroup moves the active window into a group in a direction.
+func MoveIntoGroup(d Direction) Dispatcher {
+	return command{"moveintogroup", string(d)}
+}
+
+// MoveOutOfGroup moves a window (or the active window if empty) out of a
+// group.
+func MoveOutOfGroup(window WindowSelector) Dispatcher {
+	return command{"moveoutofgroup", string(window)}
+}
+
+// MoveWindowOrGroup behaves as [MoveWindow] for ungrouped windows, moving
+// into and out of groups otherwise.
+func MoveWindowOrGroup(d Direction) Dispatcher {
+	return command{"movewindoworgroup", string(d)}
+}
+
+// MoveGroupWindow swaps the active window with the next or previous window in
+// a group.
+func MoveGroupWindow(d GroupDirection) Dispatcher {
+	return command{"movegroupwindow", string(d)}
+}
+
+// DenyWindowFromGroup prohibits the active window from becoming or being
+// inserted into group.
+func DenyWindowFromGroup(state State) Dispatcher {
+	return command{"denywindowfromgroup", string(state)}
+}
+
+// SetIgnoreGroupLock temporarily enables or disables binds:ignore_group_lock.
+func SetIgnoreGroupLock(state State) Dispatcher {
+	return command{"setignoregrouplock", string(state)}
+}
+
+// Global executes a Global Shortcut using the GlobalShortcuts portal.
+func Global(name string) Dispatcher {
+	return command{"global", name}
+}
+
+// Submap changes the current mapping group. If name is empty, resets to the
+// default submap.
+func Submap(name string) Dispatcher {
+	if name == "" {
+		return command{"submap", "reset"}
+	}
+
+	return command{"submap", name}
+}
+
+// LayoutMsg sends a message to the current layout, e.g.:
+// "layoutmsg swapwithmaster master".
+func LayoutMsg(msg string) Dispatcher {
+	return command{"layoutmsg", msg}
+}
+
+// Event emits a custom event with arbitrary data to the event socket, that
+// will be received as 'custom>>data'. The data should not contain new lines.
+func Event(data string) Dispatcher {
+	return command{"event", data}
+}
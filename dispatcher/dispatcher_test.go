@@ -0,0 +1,107 @@
+package dispatcher
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thiagokokada/hyprland-go/internal/assert"
+)
+
+func TestDispatchers(t *testing.T) {
+	tests := []struct {
+		dispatcher Dispatcher
+		want       string
+	}{
+		{Exec("kitty"), "exec kitty"},
+		{ExecWithRules([]string{"workspace 2 silent", "float"}, "kitty"), "exec [workspace 2 silent;float] kitty"},
+		{Raw("hyprexpo:expo", "toggle"), "hyprexpo:expo toggle"},
+		{KillActive(), "killactive"},
+		{Workspace(WorkspaceID(1)), "workspace 1"},
+		{Workspace(WorkspaceName("web")), "workspace name:web"},
+		{Workspace(RelativeWorkspace(1)), "workspace +1"},
+		{Workspace(RelativeWorkspace(-1)), "workspace -1"},
+		{Workspace(MonitorRelativeWorkspace(-2)), "workspace m-2"},
+		{Workspace(MonitorRelativeWorkspaceWithEmpty(1)), "workspace r+1"},
+		{Workspace(OpenRelativeWorkspace(1)), "workspace e+1"},
+		{Workspace(PreviousWorkspace), "workspace previous"},
+		{Workspace(SpecialWorkspace("")), "workspace special"},
+		{MoveToWorkspace(SpecialWorkspace("scratch"), ""), "movetoworkspace special:scratch"},
+		{MoveToWorkspaceSilent(WorkspaceID(3), WindowAddress("1234")), "movetoworkspacesilent 3,address:0x1234"},
+		{ToggleFloating(""), "togglefloating"},
+		{Fullscreen(FullscreenModeMaximize), "fullscreen 1"},
+		{DPMS(Off, ""), "dpms off"},
+		{DPMS(Toggle, MonitorName("DP-1")), "dpms toggle DP-1"},
+		{MoveFocus(Left), "movefocus l"},
+		{MoveWindowToMonitor(RelativeMonitor(1)), "movewindow mon:+1"},
+		{CenterWindow(true), "centerwindow 1"},
+		{ResizeActive(10, -10), "resizeactive 10 -10"},
+		{ResizeActiveExact(1280, 720), "resizeactive exact 1280 720"},
+		{MoveWindowPixel(10, 20, WindowPid(42)), "movewindowpixel 10 20,pid:42"},
+		{CycleNext(true), "cyclenext prev"},
+		{FocusWindow(WindowAddress("1234")), "focuswindow address:0x1234"},
+		{FocusMonitor(CurrentMonitor), "focusmonitor current"},
+		{SplitRatio(-0.1), "splitratio -0.1"},
+		{SplitRatioExact(0.5), "splitratio exact 0.5"},
+		{RenameWorkspace(1, "web"), "renameworkspace 1 web"},
+		{MoveWorkspaceToMonitor(WorkspaceID(1), MonitorID(0)), "moveworkspacetomonitor 1 0"},
+		{AlterZOrder(Top, ""), "alterzorder top"},
+		{SendShortcut("SUPER", "F", ActiveWindow), "sendshortcut SUPER, F, activewindow"},
+		{ToggleGroup(), "togglegroup"},
+		{ChangeGroupActive(Back), "changegroupactive b"},
+		{ChangeGroupActiveIndex(2), "changegroupactive 2"},
+		{LockGroups(LockToggle), "lockgroups toggle"},
+		{MoveIntoGroup(Up), "moveintogroup u"},
+		{MoveOutOfGroup(""), "moveoutofgroup"},
+		{MoveWindowOrGroup(Down), "movewindoworgroup d"},
+		{MoveGroupWindow(Forward), "movegroupwindow f"},
+		{DenyWindowFromGroup(On), "denywindowfromgroup on"},
+		{Submap(""), "submap reset"},
+		{LayoutMsg("swapwithmaster master"), "layoutmsg swapwithmaster master"},
+		{Event(`{"foo": "bar, baz"}`), `event {"foo": "bar, baz"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.dispatcher.String(), tt.want)
+		})
+	}
+}
+
+func TestStrings(t *testing.T) {
+	got := Strings(ToggleGroup(), MoveFocus(Right))
+	assert.DeepEqual(t, got, []string{"togglegroup", "movefocus r"})
+}
+
+func TestWindowSelector(t *testing.T) {
+	tests := []struct {
+		selector func() (WindowSelector, error)
+		want     WindowSelector
+		wantErr  bool
+	}{
+		{func() (WindowSelector, error) { return WindowAddress("0x1234"), nil }, "address:0x1234", false},
+		{func() (WindowSelector, error) { return WindowAddress("80e62df0"), nil }, "address:0x80e62df0", false},
+		{func() (WindowSelector, error) { return WindowPid(1234), nil }, "pid:1234", false},
+		{func() (WindowSelector, error) { return WindowClass("^(kitty)$") }, "class:^(kitty)$", false},
+		{func() (WindowSelector, error) { return WindowTitle("foo, bar") }, `title:foo\x2c bar`, false},
+		{func() (WindowSelector, error) { return WindowTitle(`foo\, bar`) }, `title:foo\x2c bar`, false},
+		{func() (WindowSelector, error) { return WindowTitle("[,;]") }, `title:[\x2c\x3b]`, false},
+		{func() (WindowSelector, error) { return WindowTitle(`\.\{`) }, `title:\.\{`, false},
+		{func() (WindowSelector, error) { return WindowInitialClass("a{2}") }, "initialclass:a{2}", false},
+		{func() (WindowSelector, error) { return WindowInitialTitle(ExactRegex("a.b")) }, `initialtitle:^a\.b$`, false},
+		{func() (WindowSelector, error) { return WindowTitle("a{1,3}") }, "", true},
+		{func() (WindowSelector, error) { return WindowTitle("(foo") }, "", true},
+		{func() (WindowSelector, error) { return WindowTitle("foo\nbar") }, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			got, err := tt.selector()
+			assert.Equal(t, got, tt.want)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.True(t, errors.Is(err, ErrInvalidSelector))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
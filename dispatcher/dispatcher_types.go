@@ -0,0 +1,334 @@
+package dispatcher
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Dispatcher is the interface implemented by all dispatchers in this package.
+// You can find more information about each dispatcher in the main Hyprland
+// Wiki: https://wiki.hyprland.org/Configuring/Dispatchers/.
+type Dispatcher interface {
+	// String returns the dispatcher serialised in the format expected by
+	// Hyprland's socket, e.g.: "movefocus l".
+	String() string
+}
+
+// Generic implementation of [Dispatcher].
+type command struct {
+	name string
+	arg  string
+}
+
+func (c command) String() string {
+	if c.arg == "" {
+		return c.name
+	}
+
+	return c.name + " " + c.arg
+}
+
+// Direction used by dispatchers like [MoveFocus] and [MoveWindow].
+type Direction string
+
+const (
+	Left  Direction = "l"
+	Right Direction = "r"
+	Up    Direction = "u"
+	Down  Direction = "d"
+)
+
+// GroupDirection used by dispatchers like [ChangeGroupActive] and
+// [MoveGroupWindow].
+type GroupDirection string
+
+const (
+	Back    GroupDirection = "b"
+	Forward GroupDirection = "f"
+)
+
+// State used by dispatchers that can be turned on, off or toggled, e.g.:
+// [DenyWindowFromGroup].
+type State string
+
+const (
+	On     State = "on"
+	Off    State = "off"
+	Toggle State = "toggle"
+)
+
+// LockState used by dispatchers like [LockGroups] and [LockActiveGroup].
+type LockState string
+
+const (
+	Lock       LockState = "lock"
+	Unlock     LockState = "unlock"
+	LockToggle LockState = "toggle"
+)
+
+// FullscreenMode used by [Fullscreen] dispatcher.
+type FullscreenMode int
+
+const (
+	FullscreenModeFullscreen FullscreenMode = iota
+	FullscreenModeMaximize
+)
+
+// ZOrder used by [AlterZOrder] dispatcher.
+type ZOrder string
+
+const (
+	Top    ZOrder = "top"
+	Bottom ZOrder = "bottom"
+)
+
+// WorkspaceSelector selects a workspace, see
+// https://wiki.hyprland.org/Configuring/Dispatchers/#workspaces.
+// Use one of the constructors or constants below to create one.
+type WorkspaceSelector string
+
+const (
+	// Previous workspace.
+	PreviousWorkspace WorkspaceSelector = "previous"
+	// Previous workspace on the current monitor.
+	PreviousWorkspacePerMonitor WorkspaceSelector = "previous_per_monitor"
+	// First available empty workspace.
+	EmptyWorkspace WorkspaceSelector = "empty"
+	// First available empty workspace on the current monitor.
+	EmptyWorkspaceOnMonitor WorkspaceSelector = "emptym"
+	// Next available empty workspace.
+	NextEmptyWorkspace WorkspaceSelector = "emptyn"
+)
+
+// Workspace selected by its ID, e.g.: "1".
+func WorkspaceID(id int) WorkspaceSelector {
+	return WorkspaceSelector(strconv.Itoa(id))
+}
+
+// Workspace selected by its name, e.g.: "name:web".
+func WorkspaceName(name string) WorkspaceSelector {
+	return WorkspaceSelector("name:" + name)
+}
+
+// Workspace relative to the current one by ID, e.g.: "+1" or "-1".
+func RelativeWorkspace(n int) WorkspaceSelector {
+	return WorkspaceSelector(fmt.Sprintf("%+d", n))
+}
+
+// Workspace relative to the current one in the current monitor, e.g.: "m+1".
+func MonitorRelativeWorkspace(n int) WorkspaceSelector {
+	return WorkspaceSelector(fmt.Sprintf("m%+d", n))
+}
+
+// Workspace relative to the current one in the current monitor including
+// empty workspaces, e.g.: "r+1".
+func MonitorRelativeWorkspaceWithEmpty(n int) WorkspaceSelector {
+	return WorkspaceSelector(fmt.Sprintf("r%+d", n))
+}
+
+// Workspace relative to the current one counting only open workspaces, e.g.:
+// "e+1".
+func OpenRelativeWorkspace(n int) WorkspaceSelector {
+	return WorkspaceSelector(fmt.Sprintf("e%+d", n))
+}
+
+// Special workspace, e.g.: "special:scratchpad". If name is empty, the
+// default special workspace is used.
+func SpecialWorkspace(name string) WorkspaceSelector {
+	if name == "" {
+		return "special"
+	}
+
+	return WorkspaceSelector("special:" + name)
+}
+
+// MonitorSelector selects a monitor, see
+// https://wiki.hyprland.org/Configuring/Dispatchers/#parameter-explanation.
+// Use one of the constructors or constants below to create one.
+type MonitorSelector string
+
+// Current monitor.
+const CurrentMonitor MonitorSelector = "current"
+
+// Monitor selected by its name, e.g.: "DP-1".
+func MonitorName(name string) MonitorSelector {
+	return MonitorSelector(name)
+}
+
+// Monitor selected by its ID, e.g.: "0".
+func MonitorID(id int) MonitorSelector {
+	return MonitorSelector(strconv.Itoa(id))
+}
+
+// Monitor selected by its direction from the current one, e.g.: "l".
+func MonitorDirection(d Direction) MonitorSelector {
+	return MonitorSelector(d)
+}
+
+// Monitor relative to the current one, e.g.: "+1" or "-1".
+func RelativeMonitor(n int) MonitorSelector {
+	return MonitorSelector(fmt.Sprintf("%+d", n))
+}
+
+// WindowSelector selects a window, see
+// https://wiki.hyprland.org/Configuring/Dispatchers/#parameter-explanation.
+// Use one of the constructors or constants below to create one. It can be
+// used in dispatchers, window rules, or in getprop/setprop. The zero value
+// means no window, i.e.: dispatchers that accept an optional window will use
+// the active window.
+type WindowSelector string
+
+const (
+	// Active window.
+	ActiveWindow WindowSelector = "activewindow"
+	// First floating window on the current workspace.
+	FloatingWindow WindowSelector = "floating"
+	// First tiled window on the current workspace.
+	TiledWindow WindowSelector = "tiled"
+)
+
+// Window selected by its address, e.g.: "address:0x5651d5da1b10". The "0x"
+// prefix is added if missing, since events report addresses without it.
+func WindowAddress(address string) WindowSelector {
+	if !strings.HasPrefix(address, "0x") {
+		address = "0x" + address
+	}
+
+	return WindowSelector("address:" + address)
+}
+
+// Window selected by its process ID, e.g.: "pid:1234".
+func WindowPid(pid int) WindowSelector {
+	return WindowSelector("pid:" + strconv.Itoa(pid))
+}
+
+// Window selected by a regex matching its class, e.g.: "class:^(kitty)$".
+// Returns an error if the regex is invalid.
+func WindowClass(regex string) (WindowSelector, error) {
+	return regexSelector("class", regex)
+}
+
+// Window selected by a regex matching its title.
+// Returns an error if the regex is invalid.
+func WindowTitle(regex string) (WindowSelector, error) {
+	return regexSelector("title", regex)
+}
+
+// Window selected by a regex matching its initial class.
+// Returns an error if the regex is invalid.
+func WindowInitialClass(regex string) (WindowSelector, error) {
+	return regexSelector("initialclass", regex)
+}
+
+// Window selected by a regex matching its initial title.
+// Returns an error if the regex is invalid.
+func WindowInitialTitle(regex string) (WindowSelector, error) {
+	return regexSelector("initialtitle", regex)
+}
+
+// ExactRegex returns a regex that matches exactly the string s, useful with
+// the regex based selectors, e.g.: WindowClass(ExactRegex("org.gnome.Nautilus")).
+func ExactRegex(s string) string {
+	return "^" + regexp.QuoteMeta(s) + "$"
+}
+
+func (w WindowSelector) String() string {
+	return string(w)
+}
+
+// Validates the regex and quotes characters that have special meaning for
+// Hyprland's parser: ',' separates arguments in dispatchers and window rules,
+// and ';' separates commands in batch requests. Hyprland uses RE2 for window
+// matching, so we can use the same semantics as Go's regexp.
+func regexSelector(prefix string, regex string) (WindowSelector, error) {
+	if strings.ContainsAny(regex, "\r\n") {
+		return "", fmt.Errorf("%w: regex contains a newline: %q", ErrInvalidSelector, regex)
+	}
+
+	if _, err := regexp.Compile(regex); err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidSelector, err)
+	}
+
+	quoted, err := quoteRegex(regex)
+	if err != nil {
+		return "", err
+	}
+
+	return WindowSelector(prefix + ":" + quoted), nil
+}
+
+func quoteRegex(regex string) (string, error) {
+	var sb strings.Builder
+
+	escaped := false
+	inClass := false
+	// Position of the last '{' outside a character class, or -1
+	openBrace := -1
+
+	for i := 0; i < len(regex); i++ {
+		ch := regex[i]
+
+		if escaped {
+			escaped = false
+
+			switch ch {
+			case ',':
+				sb.WriteString(`\x2c`)
+			case ';':
+				sb.WriteString(`\x3b`)
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(ch)
+			}
+
+			continue
+		}
+
+		switch {
+		case ch == '\\':
+			escaped = true
+
+			continue
+		case ch == '[' && !inClass:
+			inClass = true
+		case ch == ']' && inClass:
+			inClass = false
+		case ch == '{' && !inClass:
+			openBrace = i
+		case ch == '}':
+			openBrace = -1
+		case ch == ',' && openBrace >= 0 && isDigits(regex[openBrace+1:i]):
+			// A comma inside a repetition, e.g.: 'a{1,3}', can not
+			// be escaped
+			return "", fmt.Errorf(
+				"%w: repetition with comma is not supported: %q",
+				ErrInvalidSelector,
+				regex,
+			)
+		case ch == ',':
+			sb.WriteString(`\x2c`)
+
+			continue
+		case ch == ';':
+			sb.WriteString(`\x3b`)
+
+			continue
+		}
+
+		sb.WriteByte(ch)
+	}
+
+	return sb.String(), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
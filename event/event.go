@@ -1,10 +1,13 @@
 package event
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"net"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -17,32 +20,90 @@ const (
 	sep     = ">>"
 )
 
+// Returned when an event payload does not match the expected format, e.g.:
+// it has less fields than expected.
+var ErrMalformedEvent = errors.New("malformed event")
+
 // Initiate a new client or panic.
 // This should be the preferred method for user scripts, since it will
 // automatically find the proper socket to connect and use the
-// HYPRLAND_INSTANCE_SIGNATURE for the current user.
+// HYPRLAND_INSTANCE_SIGNATURE for the current user (or the oldest running
+// instance if it is not set, see helpers.GetSocket). Before, an unset
+// HYPRLAND_INSTANCE_SIGNATURE was an error, now it only is if no instance is
+// running.
 // If you need to connect to arbitrary user instances or need a method that
-// will not panic on error, use [NewClient] instead.
-func MustClient() *EventClient {
-	return assert.Must1(NewClient(
-		assert.Must1(helpers.GetSocket(helpers.EventSocket))),
-	)
+// will not panic on error, use [NewClient] or [NewClientForInstance] instead.
+// With [WithReconnect], the socket is resolved again when reconnecting, so the
+// client follows the current Hyprland instance, e.g.: after a restart.
+func MustClient(opts ...ClientOption) *EventClient {
+	resolve := func() (string, error) { return helpers.GetSocket(helpers.EventSocket) }
+
+	c := assert.Must1(NewClient(assert.Must1(resolve()), opts...))
+	c.resolve = resolve
+
+	return c
 }
 
 // Initiate a new event client.
 // Receive as parameters a socket that is generally localised in
 // '$XDG_RUNTIME_DIR/hypr/$HYPRLAND_INSTANCE_SIGNATURE/.socket2.sock'.
-func NewClient(socket string) (*EventClient, error) {
+// Optionally receives a list of [ClientOption] to customise the client.
+func NewClient(socket string, opts ...ClientOption) (*EventClient, error) {
 	conn, err := net.Dial("unix", socket)
 	if err != nil {
 		return nil, fmt.Errorf("error while connecting to socket: %w", err)
 	}
 
-	return &EventClient{conn: conn}, err
+	c := &EventClient{conn: conn, socket: socket}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c, err
+}
+
+// Initiate a new event client for a Hyprland instance, e.g.: one returned by
+// [helpers.Instances] or [helpers.FindInstance]. Useful when
+// HYPRLAND_INSTANCE_SIGNATURE is not set or to connect to a specific
+// instance.
+// Optionally receives a list of [ClientOption] to customise the client.
+func NewClientForInstance(instance helpers.Instance, opts ...ClientOption) (*EventClient, error) {
+	return NewClient(instance.Socket(helpers.EventSocket), opts...)
+}
+
+// WithReconnect makes [EventClient.Subscribe] and [EventClient.Events]
+// reconnect once the connection to Hyprland is lost, e.g.: when Hyprland is
+// restarted, instead of returning an error. Clients created with
+// [MustClient] resolve the socket again (see helpers.GetSocket), falling back
+// to the socket used to create the client. Clients created with [NewClient]
+// or [NewClientForInstance] always reconnect to the same socket, so they
+// never switch to a different Hyprland instance.
+// Events emitted while disconnected are lost, see [ConnectionHandler] and
+// [ReconnectOptions] for how to be notified about it.
+func WithReconnect(opts ReconnectOptions) ClientOption {
+	return func(c *EventClient) {
+		c.reconnect = &opts
+	}
+}
+
+// WithErrorHandler sets a function that is called for each event that can
+// not be parsed, with an error wrapping [ErrMalformedEvent], e.g.: an event
+// with less fields than expected. The event is skipped and the client keeps
+// receiving the next ones. Without it, those events are skipped silently.
+func WithErrorHandler(f func(err error)) ClientOption {
+	return func(c *EventClient) {
+		c.onError = f
+	}
 }
 
 // Close the underlying connection.
 func (c *EventClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+
 	err := c.conn.Close()
 	if err != nil {
 		return fmt.Errorf("error while closing socket: %w", err)
@@ -53,32 +114,46 @@ func (c *EventClient) Close() error {
 
 // Low-level receive event method, should be avoided unless there is no
 // alternative.
+// Blocks until at least one complete event line is received, returning all
+// complete lines available. Partial lines are kept between calls (including
+// calls that returned an error), so each event is returned exactly once and
+// in order, independent of its size.
 func (c *EventClient) Receive(ctx context.Context) ([]ReceivedData, error) {
 	buf := make([]byte, bufSize)
 
-	n, err := readWithContext(ctx, c.conn, buf)
-	if err != nil {
-		return nil, fmt.Errorf("error while reading from socket: %w", err)
+	// Read until we have at least one complete line
+	for bytes.IndexByte(c.pending, '\n') < 0 {
+		n, err := readWithContext(ctx, c.conn, buf)
+		// Keep any data read, even in case of errors
+		c.pending = append(c.pending, buf[:n]...)
+
+		if err != nil {
+			return nil, fmt.Errorf("error while reading from socket: %w", err)
+		}
 	}
 
-	buf = buf[:n]
+	// Only process complete lines, keeping the rest for the next call
+	idx := bytes.LastIndexByte(c.pending, '\n')
+	lines := string(c.pending[:idx])
+	c.pending = append(c.pending[:0], c.pending[idx+1:]...)
 
 	var recv []ReceivedData //nolint:prealloc
 
-	raw := strings.Split(string(buf), "\n")
-	for _, event := range raw {
-		if event == "" {
+	for _, event := range strings.Split(lines, "\n") {
+		eventType, data, found := strings.Cut(event, sep)
+		if !found || eventType == "" {
 			continue
 		}
 
-		split := strings.Split(event, sep)
-		if len(split) < 2 || split[0] == "" || split[1] == "" || split[1] == "," {
+		// Events without payload, e.g.: 'activewindow>>,' when no window
+		// is focused, are ignored unless their schema allows it
+		if (data == "" || data == ",") && !eventSchemas[EventType(eventType)].optional {
 			continue
 		}
 
 		recv = append(recv, ReceivedData{
-			Type: EventType(split[0]),
-			Data: RawData(split[1]),
+			Type: EventType(eventType),
+			Data: RawData(data),
 		})
 	}
 
@@ -88,142 +163,448 @@ func (c *EventClient) Receive(ctx context.Context) ([]ReceivedData, error) {
 // Subscribe to events.
 // You need to pass an implementation of [EventHandler] interface for each of
 // the events you want to handle and all event types you want to handle.
+// If an event payload can not be parsed, the event is skipped and the error
+// is passed to the function set with [WithErrorHandler], if any. Only errors
+// while receiving events (e.g.: the connection was lost) are returned.
+// If the client was created with [WithReconnect] and ev implements
+// [ConnectionHandler], it is notified when the connection is lost and
+// restored.
 func (c *EventClient) Subscribe(ctx context.Context, ev EventHandler, events ...EventType) error {
+	h, _ := ev.(ConnectionHandler)
+
 	for {
 		// Process an event
-		if err := receiveAndProcessEvent(ctx, c, ev, events...); err != nil {
-			return fmt.Errorf("event processing: %w", err)
+		if err := receiveAndProcessEvent(ctx, c, ev, c.reportErr, events...); err != nil {
+			if err := c.reconnectOrErr(ctx, err, h); err != nil {
+				return fmt.Errorf("event processing: %w", err)
+			}
 		}
 	}
 }
 
-func readWithContext(ctx context.Context, conn net.Conn, buf []byte) (n int, err error) {
-	done := make(chan struct{})
+func readWithContext(ctx context.Context, conn net.Conn, buf []byte) (int, error) {
+	type result struct {
+		n   int
+		err error
+	}
+
+	done := make(chan result, 1)
 
 	// Start a goroutine to perform the read
 	go func() {
-		n, err = conn.Read(buf)
+		n, err := conn.Read(buf)
 
-		close(done)
+		done <- result{n, err}
 	}()
 
 	select {
-	case <-done:
-		return n, err
+	case r := <-done:
+		return r.n, r.err
 	case <-ctx.Done():
 		// Set a short deadline to unblock the Read()
-		err = conn.SetReadDeadline(time.Now())
-		if err != nil {
+		if err := conn.SetReadDeadline(time.Now()); err != nil {
 			return 0, err
 		}
+		// Make sure that the goroutine is done to avoid leaks. The read
+		// may have finished before the deadline, so we keep its result
+		r := <-done
 		// Reset read deadline
-		defer func() {
-			if e := conn.SetReadDeadline(time.Time{}); e != nil {
-				err = errors.Join(err, e)
-			}
-		}()
-		// Make sure that the goroutine is done to avoid leaks
-		<-done
+		if err := conn.SetReadDeadline(time.Time{}); err != nil {
+			return r.n, errors.Join(r.err, err, ctx.Err())
+		}
 
-		return 0, errors.Join(err, ctx.Err())
+		return r.n, errors.Join(r.err, ctx.Err())
 	}
 }
 
-func receiveAndProcessEvent(ctx context.Context, c eventClient, ev EventHandler, events ...EventType) error {
+// Pass the error from an event that can not be parsed to the function set
+// with [WithErrorHandler], if any.
+func (c *EventClient) reportErr(err error) {
+	if c.onError != nil {
+		c.onError(fmt.Errorf("event processing: %w", err))
+	}
+}
+
+// Receive and process the events. Events that can not be parsed are skipped
+// and their errors passed to report, so only errors while receiving are
+// returned.
+func receiveAndProcessEvent(ctx context.Context, c eventClient, ev EventHandler, report func(error), events ...EventType) error {
 	msg, err := c.Receive(ctx)
 	if err != nil {
 		return err
 	}
 
 	for _, data := range msg {
-		processEvent(ev, data, events)
+		if err := processEvent(ev, data, events); err != nil {
+			report(err)
+		}
 	}
 
 	return nil
 }
 
-func processEvent(ev EventHandler, msg ReceivedData, events []EventType) {
-	for _, event := range events {
-		raw := strings.Split(string(msg.Data), ",")
-
-		if msg.Type == event {
-			switch event {
-			case EventWorkspace:
-				// e.g. "1" (workspace number)
-				ev.Workspace(WorkspaceName(raw[0]))
-			case EventFocusedMonitor:
-				// idk
-				ev.FocusedMonitor(FocusedMonitor{
-					MonitorName:   MonitorName(raw[0]),
-					WorkspaceName: WorkspaceName(raw[1]),
-				})
-			case EventActiveWindow:
-				// e.g. nvim,nvim event/event.go
-				ev.ActiveWindow(ActiveWindow{
-					Name:  raw[0],
-					Title: raw[1],
-				})
-			case EventFullscreen:
-				// e.g. "true" or "false"
-				ev.Fullscreen(raw[0] == "1")
-			case EventMonitorRemoved:
-				// e.g. idk
-				ev.MonitorRemoved(MonitorName(raw[0]))
-			case EventMonitorAdded:
-				// e.g. idk
-				ev.MonitorAdded(MonitorName(raw[0]))
-			case EventCreateWorkspace:
-				// e.g. "1" (workspace number)
-				ev.CreateWorkspace(WorkspaceName(raw[0]))
-			case EventDestroyWorkspace:
-				// e.g. "1" (workspace number)
-				ev.DestroyWorkspace(WorkspaceName(raw[0]))
-			case EventMoveWorkspace:
-				// e.g. idk
-				ev.MoveWorkspace(MoveWorkspace{
-					WorkspaceName: WorkspaceName(raw[0]),
-					MonitorName:   MonitorName(raw[1]),
-				})
-			case EventActiveLayout:
-				// e.g. AT Translated Set 2 keyboard,Russian
-				ev.ActiveLayout(ActiveLayout{
-					Type: raw[0],
-					Name: raw[1],
-				})
-			case EventOpenWindow:
-				// e.g. 80864f60,1,Alacritty,Alacritty
-				ev.OpenWindow(OpenWindow{
-					Address:       raw[0],
-					WorkspaceName: WorkspaceName(raw[1]),
-					Class:         raw[2],
-					Title:         raw[3],
-				})
-			case EventCloseWindow:
-				// e.g. 5
-				ev.CloseWindow(CloseWindow{
-					Address: raw[0],
-				})
-			case EventMoveWindow:
-				// e.g. 5
-				ev.MoveWindow(MoveWindow{
-					Address:       raw[0],
-					WorkspaceName: WorkspaceName(raw[1]),
-				})
-			case EventOpenLayer:
-				// e.g. wofi
-				ev.OpenLayer(OpenLayer(raw[0]))
-			case EventCloseLayer:
-				// e.g. wofi
-				ev.CloseLayer(CloseLayer(raw[0]))
-			case EventSubMap:
-				// e.g. idk
-				ev.SubMap(SubMap(raw[0]))
-			case EventScreencast:
-				ev.Screencast(Screencast{
-					Sharing: raw[0] == "1",
-					Owner:   raw[1],
-				})
-			}
+// Split the event data in the fields described by the schema. Fields before
+// the rest field are split from the left, and fields after it from the right,
+// so only the rest field may contain commas.
+func (s eventSchema) split(data string) ([]string, error) {
+	raw := make([]string, s.fields)
+
+	for i := 0; i < s.rest; i++ {
+		before, after, found := strings.Cut(data, ",")
+		if !found {
+			return nil, fmt.Errorf("want %d fields, got %d", s.fields, i+1)
+		}
+
+		raw[i], data = before, after
+	}
+
+	for i := s.fields - 1; i > s.rest; i-- {
+		idx := strings.LastIndexByte(data, ',')
+		if idx < 0 {
+			return nil, fmt.Errorf("want %d fields, got %d", s.fields, s.rest+s.fields-i)
+		}
+
+		raw[i], data = data[idx+1:], data[:idx]
+	}
+
+	raw[s.rest] = data
+
+	return raw, nil
+}
+
+func parseWorkspaceId(s string) (WorkspaceId, error) {
+	id, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("%w: invalid workspace id: %w", ErrMalformedEvent, err)
+	}
+
+	return WorkspaceId(id), nil
+}
+
+func processEvent(ev EventHandler, msg ReceivedData, events []EventType) error {
+	if !subscribed(events, msg.Type) {
+		return nil
+	}
+
+	e, err := parseEvent(msg)
+	if err != nil {
+		return err
+	}
+
+	handleEvent(ev, e)
+
+	return nil
+}
+
+// Returns true if the event type is in events, or if events includes
+// [EventWildcard].
+func subscribed(events []EventType, t EventType) bool {
+	return slices.Contains(events, t) || slices.Contains(events, EventWildcard)
+}
+
+// Parse the received data in one of the types implementing [Event]. Returns
+// [Unknown] if the event type is unknown.
+func parseEvent(msg ReceivedData) (Event, error) {
+	schema, ok := eventSchemas[msg.Type]
+	if !ok {
+		return Unknown{Type: msg.Type, Data: msg.Data}, nil
+	}
+
+	raw, err := schema.split(string(msg.Data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s%s%s: %w", ErrMalformedEvent, msg.Type, sep, msg.Data, err)
+	}
+
+	switch msg.Type {
+	case EventWorkspace:
+		// e.g. "1" (workspace number)
+		return Workspace{WorkspaceName(raw[0])}, nil
+	case EventFocusedMonitor:
+		// idk
+		return FocusedMonitor{
+			MonitorName:   MonitorName(raw[0]),
+			WorkspaceName: WorkspaceName(raw[1]),
+		}, nil
+	case EventActiveWindow:
+		// e.g. nvim,nvim event/event.go
+		return ActiveWindow{
+			Name:  raw[0],
+			Title: raw[1],
+		}, nil
+	case EventFullscreen:
+		// e.g. "true" or "false"
+		return Fullscreen(raw[0] == "1"), nil
+	case EventMonitorRemoved:
+		// e.g. idk
+		return MonitorRemoved{MonitorName(raw[0])}, nil
+	case EventMonitorAdded:
+		// e.g. idk
+		return MonitorAdded{MonitorName(raw[0])}, nil
+	case EventCreateWorkspace:
+		// e.g. "1" (workspace number)
+		return CreateWorkspace{WorkspaceName(raw[0])}, nil
+	case EventDestroyWorkspace:
+		// e.g. "1" (workspace number)
+		return DestroyWorkspace{WorkspaceName(raw[0])}, nil
+	case EventMoveWorkspace:
+		// e.g. idk
+		return MoveWorkspace{
+			WorkspaceName: WorkspaceName(raw[0]),
+			MonitorName:   MonitorName(raw[1]),
+		}, nil
+	case EventActiveLayout:
+		// e.g. AT Translated Set 2 keyboard,Russian
+		return ActiveLayout{
+			Type: raw[0],
+			Name: raw[1],
+		}, nil
+	case EventOpenWindow:
+		// e.g. 80864f60,1,Alacritty,Alacritty
+		return OpenWindow{
+			Address:       raw[0],
+			WorkspaceName: WorkspaceName(raw[1]),
+			Class:         raw[2],
+			Title:         raw[3],
+		}, nil
+	case EventCloseWindow:
+		// e.g. 5
+		return CloseWindow{
+			Address: raw[0],
+		}, nil
+	case EventMoveWindow:
+		// e.g. 5
+		return MoveWindow{
+			Address:       raw[0],
+			WorkspaceName: WorkspaceName(raw[1]),
+		}, nil
+	case EventOpenLayer:
+		// e.g. wofi
+		return OpenLayer(raw[0]), nil
+	case EventCloseLayer:
+		// e.g. wofi
+		return CloseLayer(raw[0]), nil
+	case EventSubMap:
+		// e.g. idk
+		return SubMap(raw[0]), nil
+	case EventScreencast:
+		return Screencast{
+			Sharing: raw[0] == "1",
+			Owner:   raw[1],
+		}, nil
+	case EventWorkspaceV2:
+		// e.g. 1,1
+		id, err := parseWorkspaceId(raw[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return WorkspaceV2{
+			WorkspaceId:   id,
+			WorkspaceName: WorkspaceName(raw[1]),
+		}, nil
+	case EventFocusedMonitorV2:
+		// e.g. DP-1,1
+		id, err := parseWorkspaceId(raw[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return FocusedMonitorV2{
+			MonitorName: MonitorName(raw[0]),
+			WorkspaceId: id,
+		}, nil
+	case EventCreateWorkspaceV2:
+		// e.g. 1,1
+		id, err := parseWorkspaceId(raw[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return CreateWorkspaceV2{
+			WorkspaceId:   id,
+			WorkspaceName: WorkspaceName(raw[1]),
+		}, nil
+	case EventDestroyWorkspaceV2:
+		// e.g. 1,1
+		id, err := parseWorkspaceId(raw[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return DestroyWorkspaceV2{
+			WorkspaceId:   id,
+			WorkspaceName: WorkspaceName(raw[1]),
+		}, nil
+	case EventMoveWorkspaceV2:
+		// e.g. 1,1,DP-1
+		id, err := parseWorkspaceId(raw[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return MoveWorkspaceV2{
+			WorkspaceId:   id,
+			WorkspaceName: WorkspaceName(raw[1]),
+			MonitorName:   MonitorName(raw[2]),
+		}, nil
+	case EventRenameWorkspace:
+		// e.g. 1,web
+		id, err := parseWorkspaceId(raw[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return RenameWorkspace{
+			WorkspaceId: id,
+			NewName:     WorkspaceName(raw[1]),
+		}, nil
+	case EventActiveWindowV2:
+		// e.g. 80e62df0, or empty if no window is focused
+		return ActiveWindowV2{
+			Address: raw[0],
+		}, nil
+	case EventWindowTitle:
+		// e.g. 80e62df0
+		return WindowTitle{
+			Address: raw[0],
+		}, nil
+	case EventWindowTitleV2:
+		// e.g. 80e62df0,nvim event/event.go
+		return WindowTitleV2{
+			Address: raw[0],
+			Title:   raw[1],
+		}, nil
+	case EventUrgent:
+		// e.g. 80e62df0
+		return Urgent{
+			Address: raw[0],
+		}, nil
+	case EventChangeFloatingMode:
+		// e.g. 80e62df0,1
+		return ChangeFloatingMode{
+			Address:  raw[0],
+			Floating: raw[1] == "1",
+		}, nil
+	case EventPin:
+		// e.g. 80e62df0,1
+		return Pin{
+			Address: raw[0],
+			Pinned:  raw[1] == "1",
+		}, nil
+	case EventMinimized:
+		// e.g. 80e62df0,1
+		return Minimized{
+			Address:   raw[0],
+			Minimized: raw[1] == "1",
+		}, nil
+	case EventToggleGroup:
+		// e.g. 1,80e62df0,80e62df1
+		return ToggleGroup{
+			Grouped:   raw[0] == "1",
+			Addresses: strings.Split(raw[1], ","),
+		}, nil
+	case EventMoveIntoGroup:
+		// e.g. 80e62df0
+		return MoveIntoGroup{
+			Address: raw[0],
+		}, nil
+	case EventMoveOutOfGroup:
+		// e.g. 80e62df0
+		return MoveOutOfGroup{
+			Address: raw[0],
+		}, nil
+	case EventLockGroups:
+		// e.g. 1
+		return LockGroups(raw[0] == "1"), nil
+	case EventIgnoreGroupLock:
+		// e.g. 1
+		return IgnoreGroupLock(raw[0] == "1"), nil
+	case EventCustom:
+		// e.g. {"foo": "bar"}
+		return Custom(raw[0]), nil
+	}
+
+	return Unknown{Type: msg.Type, Data: msg.Data}, nil
+}
+
+// Call the method from [EventHandler] corresponding to the event.
+func handleEvent(ev EventHandler, e Event) {
+	switch e := e.(type) {
+	case Workspace:
+		ev.Workspace(e.WorkspaceName)
+	case FocusedMonitor:
+		ev.FocusedMonitor(e)
+	case ActiveWindow:
+		ev.ActiveWindow(e)
+	case Fullscreen:
+		ev.Fullscreen(e)
+	case MonitorRemoved:
+		ev.MonitorRemoved(e.MonitorName)
+	case MonitorAdded:
+		ev.MonitorAdded(e.MonitorName)
+	case CreateWorkspace:
+		ev.CreateWorkspace(e.WorkspaceName)
+	case DestroyWorkspace:
+		ev.DestroyWorkspace(e.WorkspaceName)
+	case MoveWorkspace:
+		ev.MoveWorkspace(e)
+	case ActiveLayout:
+		ev.ActiveLayout(e)
+	case OpenWindow:
+		ev.OpenWindow(e)
+	case CloseWindow:
+		ev.CloseWindow(e)
+	case MoveWindow:
+		ev.MoveWindow(e)
+	case OpenLayer:
+		ev.OpenLayer(e)
+	case CloseLayer:
+		ev.CloseLayer(e)
+	case SubMap:
+		ev.SubMap(e)
+	case Screencast:
+		ev.Screencast(e)
+	case WorkspaceV2:
+		ev.WorkspaceV2(e)
+	case FocusedMonitorV2:
+		ev.FocusedMonitorV2(e)
+	case CreateWorkspaceV2:
+		ev.CreateWorkspaceV2(e)
+	case DestroyWorkspaceV2:
+		ev.DestroyWorkspaceV2(e)
+	case MoveWorkspaceV2:
+		ev.MoveWorkspaceV2(e)
+	case RenameWorkspace:
+		ev.RenameWorkspace(e)
+	case ActiveWindowV2:
+		ev.ActiveWindowV2(e)
+	case WindowTitle:
+		ev.WindowTitle(e)
+	case WindowTitleV2:
+		ev.WindowTitleV2(e)
+	case Urgent:
+		ev.Urgent(e)
+	case ChangeFloatingMode:
+		ev.ChangeFloatingMode(e)
+	case Pin:
+		ev.Pin(e)
+	case Minimized:
+		ev.Minimized(e)
+	case ToggleGroup:
+		ev.ToggleGroup(e)
+	case MoveIntoGroup:
+		ev.MoveIntoGroup(e)
+	case MoveOutOfGroup:
+		ev.MoveOutOfGroup(e)
+	case LockGroups:
+		ev.LockGroups(e)
+	case IgnoreGroupLock:
+		ev.IgnoreGroupLock(e)
+	case Custom:
+		ev.Custom(e)
+	case Unknown:
+		if h, ok := ev.(UnknownHandler); ok {
+			h.Unknown(e)
 		}
 	}
 }
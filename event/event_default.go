@@ -22,3 +22,26 @@ func (e *DefaultEventHandler) OpenLayer(OpenLayer)            {}
 func (e *DefaultEventHandler) CloseLayer(CloseLayer)          {}
 func (e *DefaultEventHandler) SubMap(SubMap)                  {}
 func (e *DefaultEventHandler) Screencast(Screencast)          {}
+
+func (e *DefaultEventHandler) WorkspaceV2(WorkspaceV2)               {}
+func (e *DefaultEventHandler) FocusedMonitorV2(FocusedMonitorV2)     {}
+func (e *DefaultEventHandler) CreateWorkspaceV2(CreateWorkspaceV2)   {}
+func (e *DefaultEventHandler) DestroyWorkspaceV2(DestroyWorkspaceV2) {}
+func (e *DefaultEventHandler) MoveWorkspaceV2(MoveWorkspaceV2)       {}
+func (e *DefaultEventHandler) RenameWorkspace(RenameWorkspace)       {}
+
+func (e *DefaultEventHandler) ActiveWindowV2(ActiveWindowV2)         {}
+func (e *DefaultEventHandler) WindowTitle(WindowTitle)               {}
+func (e *DefaultEventHandler) WindowTitleV2(WindowTitleV2)           {}
+func (e *DefaultEventHandler) Urgent(Urgent)                         {}
+func (e *DefaultEventHandler) ChangeFloatingMode(ChangeFloatingMode) {}
+func (e *DefaultEventHandler) Pin(Pin)                               {}
+func (e *DefaultEventHandler) Minimized(Minimized)                   {}
+
+func (e *DefaultEventHandler) ToggleGroup(ToggleGroup)         {}
+func (e *DefaultEventHandler) MoveIntoGroup(MoveIntoGroup)     {}
+func (e *DefaultEventHandler) MoveOutOfGroup(MoveOutOfGroup)   {}
+func (e *DefaultEventHandler) LockGroups(LockGroups)           {}
+func (e *DefaultEventHandler) IgnoreGroupLock(IgnoreGroupLock) {}
+
+func (e *DefaultEventHandler) Custom(Custom) {}
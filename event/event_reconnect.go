@@ -0,0 +1,117 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+)
+
+const (
+	defaultMinBackoff = 100 * time.Millisecond
+	defaultMaxBackoff = 5 * time.Second
+)
+
+// Reconnect to Hyprland after err, if the client was created with
+// [WithReconnect]. Returns err if it is not possible to reconnect, e.g.: the
+// context is done or the client was closed.
+func (c *EventClient) reconnectOrErr(ctx context.Context, err error, h ConnectionHandler) error {
+	if c.reconnect == nil || ctx.Err() != nil || c.isClosed() {
+		return err
+	}
+
+	opts := c.reconnect
+
+	// Ignore errors since the connection is already broken
+	_ = c.conn.Close()
+	// The partial line is lost together with the connection
+	c.pending = c.pending[:0]
+
+	if opts.OnDisconnect != nil {
+		opts.OnDisconnect(err)
+	}
+
+	if h != nil {
+		h.Disconnected(err)
+	}
+
+	backoff, maxBackoff := opts.MinBackoff, opts.MaxBackoff
+	if backoff <= 0 {
+		backoff = defaultMinBackoff
+	}
+
+	if maxBackoff <= 0 {
+		maxBackoff = defaultMaxBackoff
+	}
+
+	for {
+		timer := time.NewTimer(backoff)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+
+		conn, dialErr := c.dial()
+		if dialErr != nil {
+			backoff = min(2*backoff, maxBackoff)
+
+			continue
+		}
+
+		c.mu.Lock()
+		if c.closed {
+			c.mu.Unlock()
+			conn.Close()
+
+			return errors.Join(err, net.ErrClosed)
+		}
+		c.conn = conn
+		c.mu.Unlock()
+
+		if opts.OnReconnect != nil {
+			opts.OnReconnect()
+		}
+
+		if h != nil {
+			h.Reconnected()
+		}
+
+		return nil
+	}
+}
+
+// Connect to the socket used to create the client, or if it was created with
+// MustClient, to the socket of the current Hyprland instance, falling back to
+// the socket used to create the client.
+func (c *EventClient) dial() (net.Conn, error) {
+	sockets := []string{c.socket}
+	if c.resolve != nil {
+		if socket, err := c.resolve(); err == nil && socket != c.socket {
+			sockets = []string{socket, c.socket}
+		}
+	}
+
+	var errs []error
+
+	for _, socket := range sockets {
+		conn, err := net.Dial("unix", socket)
+		if err == nil {
+			return conn, nil
+		}
+
+		errs = append(errs, err)
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func (c *EventClient) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed
+}
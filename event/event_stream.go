@@ -0,0 +1,163 @@
+package event
+
+import (
+	"context"
+)
+
+// Event is implemented by all the types returned by [EventClient.Events],
+// e.g.: [OpenWindow], [CloseWindow] and [Workspace]. Use a type switch to
+// handle each event. Events not supported by this library are returned as
+// [Unknown].
+type Event interface {
+	// EventType returns the type of the event, e.g.: [EventOpenWindow].
+	EventType() EventType
+	// Seal the interface, so only types from this package implement it.
+	event()
+}
+
+// Events returns a channel that receives all events of the types passed as
+// parameters, in the order they are emitted by Hyprland. Uses the same
+// parsing as [EventClient.Subscribe].
+// The channel is closed once the context is done or an error happens while
+// receiving events, in this case [EventClient.Err] returns the error. Events
+// that can not be parsed are skipped, see [WithErrorHandler].
+// If the client was created with [WithReconnect], the channel is kept open
+// while reconnecting.
+// Only one of [EventClient.Events] or [EventClient.Subscribe] should be used
+// at the same time for the same client.
+func (c *EventClient) Events(ctx context.Context, types ...EventType) <-chan Event {
+	ch := make(chan Event)
+
+	go func() {
+		defer close(ch)
+
+		c.setErr(c.streamEvents(ctx, ch, types))
+	}()
+
+	return ch
+}
+
+// Err returns the error that caused the last channel returned by
+// [EventClient.Events] to be closed.
+func (c *EventClient) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.err
+}
+
+func (c *EventClient) setErr(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.err = err
+}
+
+func (c *EventClient) streamEvents(ctx context.Context, ch chan<- Event, types []EventType) error {
+	for {
+		msg, err := c.Receive(ctx)
+		if err != nil {
+			if err := c.reconnectOrErr(ctx, err, nil); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		for _, data := range msg {
+			if !subscribed(types, data.Type) {
+				continue
+			}
+
+			e, err := parseEvent(data)
+			if err != nil {
+				// Skip the event, without losing the next ones
+				c.reportErr(err)
+
+				continue
+			}
+
+			select {
+			case ch <- e:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (Workspace) EventType() EventType          { return EventWorkspace }
+func (FocusedMonitor) EventType() EventType     { return EventFocusedMonitor }
+func (ActiveWindow) EventType() EventType       { return EventActiveWindow }
+func (Fullscreen) EventType() EventType         { return EventFullscreen }
+func (MonitorRemoved) EventType() EventType     { return EventMonitorRemoved }
+func (MonitorAdded) EventType() EventType       { return EventMonitorAdded }
+func (CreateWorkspace) EventType() EventType    { return EventCreateWorkspace }
+func (DestroyWorkspace) EventType() EventType   { return EventDestroyWorkspace }
+func (MoveWorkspace) EventType() EventType      { return EventMoveWorkspace }
+func (ActiveLayout) EventType() EventType       { return EventActiveLayout }
+func (OpenWindow) EventType() EventType         { return EventOpenWindow }
+func (CloseWindow) EventType() EventType        { return EventCloseWindow }
+func (MoveWindow) EventType() EventType         { return EventMoveWindow }
+func (OpenLayer) EventType() EventType          { return EventOpenLayer }
+func (CloseLayer) EventType() EventType         { return EventCloseLayer }
+func (SubMap) EventType() EventType             { return EventSubMap }
+func (Screencast) EventType() EventType         { return EventScreencast }
+func (WorkspaceV2) EventType() EventType        { return EventWorkspaceV2 }
+func (FocusedMonitorV2) EventType() EventType   { return EventFocusedMonitorV2 }
+func (CreateWorkspaceV2) EventType() EventType  { return EventCreateWorkspaceV2 }
+func (DestroyWorkspaceV2) EventType() EventType { return EventDestroyWorkspaceV2 }
+func (MoveWorkspaceV2) EventType() EventType    { return EventMoveWorkspaceV2 }
+func (RenameWorkspace) EventType() EventType    { return EventRenameWorkspace }
+func (ActiveWindowV2) EventType() EventType     { return EventActiveWindowV2 }
+func (WindowTitle) EventType() EventType        { return EventWindowTitle }
+func (WindowTitleV2) EventType() EventType      { return EventWindowTitleV2 }
+func (Urgent) EventType() EventType             { return EventUrgent }
+func (ChangeFloatingMode) EventType() EventType { return EventChangeFloatingMode }
+func (Pin) EventType() EventType                { return EventPin }
+func (Minimized) EventType() EventType          { return EventMinimized }
+func (ToggleGroup) EventType() EventType        { return EventToggleGroup }
+func (MoveIntoGroup) EventType() EventType      { return EventMoveIntoGroup }
+func (MoveOutOfGroup) EventType() EventType     { return EventMoveOutOfGroup }
+func (LockGroups) EventType() EventType         { return EventLockGroups }
+func (IgnoreGroupLock) EventType() EventType    { return EventIgnoreGroupLock }
+func (Custom) EventType() EventType             { return EventCustom }
+func (u Unknown) EventType() EventType          { return u.Type }
+
+func (Workspace) event()          {}
+func (FocusedMonitor) event()     {}
+func (ActiveWindow) event()       {}
+func (Fullscreen) event()         {}
+func (MonitorRemoved) event()     {}
+func (MonitorAdded) event()       {}
+func (CreateWorkspace) event()    {}
+func (DestroyWorkspace) event()   {}
+func (MoveWorkspace) event()      {}
+func (ActiveLayout) event()       {}
+func (OpenWindow) event()         {}
+func (CloseWindow) event()        {}
+func (MoveWindow) event()         {}
+func (OpenLayer) event()          {}
+func (CloseLayer) event()         {}
+func (SubMap) event()             {}
+func (Screencast) event()         {}
+func (WorkspaceV2) event()        {}
+func (FocusedMonitorV2) event()   {}
+func (CreateWorkspaceV2) event()  {}
+func (DestroyWorkspaceV2) event() {}
+func (MoveWorkspaceV2) event()    {}
+func (RenameWorkspace) event()    {}
+func (ActiveWindowV2) event()     {}
+func (WindowTitle) event()        {}
+func (WindowTitleV2) event()      {}
+func (Urgent) event()             {}
+func (ChangeFloatingMode) event() {}
+func (Pin) event()                {}
+func (Minimized) event()          {}
+func (ToggleGroup) event()        {}
+func (MoveIntoGroup) event()      {}
+func (MoveOutOfGroup) event()     {}
+func (LockGroups) event()         {}
+func (IgnoreGroupLock) event()    {}
+func (Custom) event()             {}
+func (Unknown) event()            {}
@@ -1,21 +1,21 @@
 package event
 
 import (
-	"bufio"
 	"context"
 	"errors"
+	"fmt"
 	"math/rand"
-	"net"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/helpers"
+	"github.com/thiagokokada/hyprland-go/hyprlandtest"
 	"github.com/thiagokokada/hyprland-go/internal/assert"
 )
 
-const socketPath = "/tmp/bench_unix_socket.sock"
-
 type FakeEventClient struct {
 	EventClient
 }
@@ -92,10 +92,511 @@ func TestSubscribe(t *testing.T) {
 	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
 }
 
+type recordEventHandler struct {
+	DefaultEventHandler
+	workspaces    []WorkspaceName
+	activeWindows []ActiveWindow
+}
+
+func (h *recordEventHandler) Workspace(w WorkspaceName) {
+	h.workspaces = append(h.workspaces, w)
+}
+
+func (h *recordEventHandler) ActiveWindow(w ActiveWindow) {
+	h.activeWindows = append(h.activeWindows, w)
+}
+
+func TestSubscribeFakeServer(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+
+	c, err := NewClient(s.Socket)
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	go conn.Replay(
+		hyprlandtest.Event("workspace", "1"),
+		hyprlandtest.Event("activewindow", "kitty,fish"),
+		hyprlandtest.Event("openlayer", "wofi"),
+		hyprlandtest.Event("workspace", "2"),
+		hyprlandtest.Drop(),
+	)
+
+	h := &recordEventHandler{}
+	err = c.Subscribe(context.Background(), h, EventWorkspace, EventActiveWindow)
+
+	// Subscribe should return once the connection is dropped
+	assert.Error(t, err)
+	assert.DeepEqual(t, h.workspaces, []WorkspaceName{"1", "2"})
+	assert.DeepEqual(t, h.activeWindows, []ActiveWindow{{Name: "kitty", Title: "fish"}})
+}
+
+func TestEvents(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+
+	c, err := NewClient(s.Socket)
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	go conn.Replay(
+		hyprlandtest.Event("workspace", "1"),
+		hyprlandtest.Event("openlayer", "wofi"),
+		hyprlandtest.Event("openwindow", "80e62df0,2,kitty,fish"),
+		hyprlandtest.Event("closewindow", "80e62df0"),
+		hyprlandtest.Drop(),
+	)
+
+	var got []Event
+	for e := range c.Events(context.Background(), EventWorkspace, EventOpenWindow, EventCloseWindow) {
+		got = append(got, e)
+	}
+
+	assert.DeepEqual(t, got, []Event{
+		Workspace{WorkspaceName: "1"},
+		OpenWindow{Address: "80e62df0", WorkspaceName: "2", Class: "kitty", Title: "fish"},
+		CloseWindow{Address: "80e62df0"},
+	})
+	// Channel is closed once the connection is dropped
+	assert.Error(t, c.Err())
+}
+
+func TestEventsMalformed(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+
+	var errs []error
+
+	c, err := NewClient(s.Socket, WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	// The malformed event is received together with valid ones
+	go conn.Replay(
+		hyprlandtest.Step{Data: "workspace>>1\nworkspacev2>>invalid,1\nworkspace>>2\n"},
+		hyprlandtest.Event("workspace", "3"),
+		hyprlandtest.Drop(),
+	)
+
+	var got []Event
+	for e := range c.Events(context.Background(), EventWorkspace, EventWorkspaceV2) {
+		got = append(got, e)
+	}
+
+	assert.DeepEqual(t, got, []Event{
+		Workspace{WorkspaceName: "1"},
+		Workspace{WorkspaceName: "2"},
+		Workspace{WorkspaceName: "3"},
+	})
+	assert.False(t, errors.Is(c.Err(), ErrMalformedEvent))
+	assert.Equal(t, len(errs), 1)
+	assert.True(t, errors.Is(errs[0], ErrMalformedEvent))
+}
+
+func TestEventsCancel(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+
+	c, err := NewClient(s.Socket)
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	for range c.Events(ctx, AllEvents...) {
+		t.Error("unexpected event")
+	}
+
+	assert.True(t, errors.Is(c.Err(), context.DeadlineExceeded))
+}
+
+type reconnectEventHandler struct {
+	recordEventHandler
+	disconnected int
+	reconnected  chan struct{}
+}
+
+func (h *reconnectEventHandler) Disconnected(_ error) {
+	h.disconnected++
+}
+
+func (h *reconnectEventHandler) Reconnected() {
+	h.reconnected <- struct{}{}
+}
+
+func TestSubscribeReconnect(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+
+	var disconnectErr error
+
+	c, err := NewClient(s.Socket, WithReconnect(ReconnectOptions{
+		MinBackoff:   time.Millisecond,
+		OnDisconnect: func(err error) { disconnectErr = err },
+	}))
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	go conn.Replay(
+		hyprlandtest.Event("workspace", "1"),
+		hyprlandtest.Drop(),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	h := &reconnectEventHandler{reconnected: make(chan struct{})}
+	done := make(chan error)
+
+	go func() { done <- c.Subscribe(ctx, h, EventWorkspace) }()
+
+	conn = s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not reconnect")
+	}
+
+	<-h.reconnected
+
+	go conn.Replay(hyprlandtest.Event("workspace", "2"))
+
+	// Wait the second event to be processed before cancelling
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+
+	err = <-done
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Error(t, disconnectErr)
+	assert.Equal(t, h.disconnected, 1)
+	assert.DeepEqual(t, h.workspaces, []WorkspaceName{"1", "2"})
+}
+
+func TestNewClientForInstance(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+	t.Setenv("XDG_RUNTIME_DIR", s.Instance.RuntimeDir)
+
+	i, err := helpers.FindInstance(s.Instance.Signature)
+	assert.NoError(t, err)
+
+	c, err := NewClientForInstance(i)
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	if s.Accept(time.Second) == nil {
+		t.Fatal("client did not connect")
+	}
+}
+
+func TestEventsReconnectNewInstance(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+	s.Instance.Setenv(t)
+
+	c := MustClient(WithReconnect(ReconnectOptions{
+		MinBackoff: time.Millisecond,
+	}))
+
+	defer c.Close()
+
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	// Simulate Hyprland restart, that creates a new instance
+	s2 := hyprlandtest.NewEventServer(t)
+	s2.Instance.Setenv(t)
+	s.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := c.Events(ctx, EventWorkspace)
+
+	conn = s2.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not reconnect to the new instance")
+	}
+
+	go conn.Emit("workspace", "2")
+
+	assert.DeepEqual(t, <-ch, Event(Workspace{WorkspaceName: "2"}))
+	cancel()
+
+	for range ch {
+		t.Error("unexpected event")
+	}
+}
+
+func TestEventsReconnectSameInstance(t *testing.T) {
+	current := hyprlandtest.NewEventServer(t)
+	other := hyprlandtest.NewEventServer(t)
+
+	// Without HYPRLAND_INSTANCE_SIGNATURE, helpers.GetSocket would resolve
+	// to the current instance
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+	t.Setenv("XDG_RUNTIME_DIR", current.Instance.RuntimeDir)
+
+	c, err := NewClientForInstance(
+		helpers.Instance{Signature: other.Instance.Signature, Dir: other.Instance.Dir()},
+		WithReconnect(ReconnectOptions{MinBackoff: time.Millisecond}),
+	)
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	conn := other.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := c.Events(ctx, EventWorkspace)
+
+	assert.NoError(t, conn.Drop())
+
+	conn = other.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not reconnect to the same instance")
+	}
+
+	if current.Accept(10*time.Millisecond) != nil {
+		t.Fatal("client reconnected to a different instance")
+	}
+
+	go conn.Emit("workspace", "2")
+
+	assert.DeepEqual(t, <-ch, Event(Workspace{WorkspaceName: "2"}))
+}
+
+func TestSubscribeMalformed(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+
+	var errs []error
+
+	c, err := NewClient(s.Socket, WithErrorHandler(func(err error) { errs = append(errs, err) }))
+	assert.NoError(t, err)
+
+	defer c.Close()
+
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	go conn.Replay(
+		hyprlandtest.Event("workspacev2", "invalid,1"),
+		hyprlandtest.Event("activewindow", "kitty"),
+		hyprlandtest.Event("workspace", "1"),
+		hyprlandtest.Drop(),
+	)
+
+	// Malformed events are reported and skipped, without stopping the
+	// subscription
+	h := &recordEventHandler{}
+	err = c.Subscribe(context.Background(), h, EventWorkspace, EventWorkspaceV2, EventActiveWindow)
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrMalformedEvent))
+	assert.DeepEqual(t, h.workspaces, []WorkspaceName{"1"})
+	assert.Equal(t, len(errs), 2)
+
+	for _, err := range errs {
+		assert.True(t, errors.Is(err, ErrMalformedEvent))
+	}
+}
+
+type unknownEventHandler struct {
+	recordEventHandler
+	unknown []Unknown
+}
+
+func (h *unknownEventHandler) Unknown(u Unknown) {
+	h.unknown = append(h.unknown, u)
+}
+
+func TestProcessEventUnknown(t *testing.T) {
+	msgs := []ReceivedData{
+		{Type: EventWorkspace, Data: "1"},
+		{Type: "newevent", Data: "foo,bar"},
+		{Type: "plugin", Data: ""},
+	}
+
+	tests := []struct {
+		events      []EventType
+		wantUnknown []Unknown
+		wantWs      []WorkspaceName
+	}{
+		{nil, nil, nil},
+		{AllEvents, nil, []WorkspaceName{"1"}},
+		{[]EventType{"plugin"}, []Unknown{{Type: "plugin"}}, nil},
+		{
+			[]EventType{EventWildcard},
+			[]Unknown{{Type: "newevent", Data: "foo,bar"}, {Type: "plugin"}},
+			[]WorkspaceName{"1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v", tt.events), func(t *testing.T) {
+			h := &unknownEventHandler{}
+			for _, msg := range msgs {
+				assert.NoError(t, processEvent(h, msg, tt.events))
+			}
+
+			assert.DeepEqual(t, h.unknown, tt.wantUnknown)
+			assert.DeepEqual(t, h.workspaces, tt.wantWs)
+		})
+	}
+
+	// Handlers that do not implement UnknownHandler ignore unknown events
+	assert.NoError(t, processEvent(&DefaultEventHandler{}, msgs[1], []EventType{EventWildcard}))
+}
+
+func TestEventsWildcard(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+	c, conn := newFakeEventClient(t, s)
+
+	go conn.Replay(
+		hyprlandtest.Event("workspace", "1"),
+		hyprlandtest.Event("newevent", "foo,bar"),
+		hyprlandtest.Drop(),
+	)
+
+	var got []Event
+	for e := range c.Events(context.Background(), EventWildcard) {
+		got = append(got, e)
+	}
+
+	assert.DeepEqual(t, got, []Event{
+		Workspace{WorkspaceName: "1"},
+		Unknown{Type: "newevent", Data: "foo,bar"},
+	})
+	assert.Equal(t, got[1].EventType(), "newevent")
+}
+
+func TestParseEventType(t *testing.T) {
+	msg, err := (&FakeEventClient{}).Receive(context.Background())
+	assert.NoError(t, err)
+
+	for _, m := range msg {
+		e, err := parseEvent(m)
+		assert.NoError(t, err)
+		assert.Equal(t, e.EventType(), m.Type)
+	}
+}
+
+func newFakeEventClient(t *testing.T, s *hyprlandtest.EventServer) (*EventClient, *hyprlandtest.EventConn) {
+	t.Helper()
+
+	c, err := NewClient(s.Socket)
+	assert.NoError(t, err)
+	t.Cleanup(func() { c.Close() })
+
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	return c, conn
+}
+
+func TestReceiveSplitLines(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+
+	c, conn := newFakeEventClient(t, s)
+	// Make sure that events are split between reads
+	conn.ChunkSize = 509
+
+	longTitle := strings.Repeat("very long title, ", 1000)
+
+	var (
+		steps []hyprlandtest.Step
+		want  []ReceivedData
+	)
+
+	for i := 0; i < 50; i++ {
+		data := fmt.Sprintf("%d,%s", i, longTitle[:i*300])
+		steps = append(steps, hyprlandtest.Event("windowtitlev2", data))
+		want = append(want, ReceivedData{Type: EventWindowTitleV2, Data: RawData(data)})
+	}
+	// Events without payload are ignored, unless it is optional
+	steps = append(
+		steps,
+		hyprlandtest.Event("activewindow", ","),
+		hyprlandtest.Event("submap", ""),
+		hyprlandtest.Event("activewindowv2", ""),
+		hyprlandtest.Event("workspace", "1"),
+	)
+	want = append(
+		want,
+		ReceivedData{Type: EventActiveWindowV2, Data: ""},
+		ReceivedData{Type: EventWorkspace, Data: "1"},
+	)
+
+	go conn.Replay(steps...)
+
+	var got []ReceivedData
+	for len(got) < len(want) {
+		data, err := c.Receive(context.Background())
+		assert.NoError(t, err)
+
+		if err != nil {
+			break
+		}
+
+		got = append(got, data...)
+	}
+
+	assert.DeepEqual(t, got, want)
+}
+
+func TestReceiveCancelKeepsPartialLine(t *testing.T) {
+	s := hyprlandtest.NewEventServer(t)
+	c, conn := newFakeEventClient(t, s)
+
+	assert.NoError(t, conn.Replay(hyprlandtest.Step{Data: "workspace>>"}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Receive(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	assert.NoError(t, conn.Replay(hyprlandtest.Step{Data: "1\nworkspace>>2\n"}))
+
+	data, err := c.Receive(context.Background())
+	assert.NoError(t, err)
+	assert.DeepEqual(t, data, []ReceivedData{
+		{Type: EventWorkspace, Data: "1"},
+		{Type: EventWorkspace, Data: "2"},
+	})
+}
+
 func TestProcessEvent(t *testing.T) {
 	h := &FakeEventHandler{t: t}
 	c := &FakeEventClient{}
-	err := receiveAndProcessEvent(context.Background(), c, h, AllEvents...)
+	err := receiveAndProcessEvent(context.Background(), c, h, func(err error) { t.Error(err) }, AllEvents...)
 	assert.NoError(t, err)
 }
 
@@ -175,6 +676,82 @@ func (f *FakeEventClient) Receive(context.Context) ([]ReceivedData, error) {
 			Type: EventScreencast,
 			Data: "1,0",
 		},
+		{
+			Type: EventWorkspaceV2,
+			Data: "1,1",
+		},
+		{
+			Type: EventFocusedMonitorV2,
+			Data: "1,1",
+		},
+		{
+			Type: EventCreateWorkspaceV2,
+			Data: "1,1",
+		},
+		{
+			Type: EventDestroyWorkspaceV2,
+			Data: "1,1",
+		},
+		{
+			Type: EventMoveWorkspaceV2,
+			Data: "1,1,1",
+		},
+		{
+			Type: EventRenameWorkspace,
+			Data: "1,1",
+		},
+		{
+			Type: EventActiveWindowV2,
+			Data: "80e62df0",
+		},
+		{
+			Type: EventWindowTitle,
+			Data: "80e62df0",
+		},
+		{
+			Type: EventWindowTitleV2,
+			Data: "80e62df0,nvim foo, bar",
+		},
+		{
+			Type: EventUrgent,
+			Data: "80e62df0",
+		},
+		{
+			Type: EventChangeFloatingMode,
+			Data: "80e62df0,1",
+		},
+		{
+			Type: EventPin,
+			Data: "80e62df0,1",
+		},
+		{
+			Type: EventMinimized,
+			Data: "80e62df0,1",
+		},
+		{
+			Type: EventToggleGroup,
+			Data: "1,80e62df0,80e62df1",
+		},
+		{
+			Type: EventMoveIntoGroup,
+			Data: "80e62df0",
+		},
+		{
+			Type: EventMoveOutOfGroup,
+			Data: "80e62df0",
+		},
+		{
+			Type: EventLockGroups,
+			Data: "1",
+		},
+		{
+			Type: EventIgnoreGroupLock,
+			Data: "1",
+		},
+		{
+			Type: EventCustom,
+			Data: `{"foo": "bar, baz"}`,
+		},
 	}, nil
 }
 
@@ -255,21 +832,174 @@ func (h *FakeEventHandler) Screencast(s Screencast) {
 	assert.Equal(h.t, s.Sharing, true)
 }
 
-func BenchmarkReceive(b *testing.B) {
-	go RandomStringServer()
+func (h *FakeEventHandler) WorkspaceV2(w WorkspaceV2) {
+	assert.Equal(h.t, w.WorkspaceId, 1)
+	assert.Equal(h.t, w.WorkspaceName, "1")
+}
 
-	// Make sure the socket exist
-	for i := 0; i < 10; i++ {
-		time.Sleep(100 * time.Millisecond)
+func (h *FakeEventHandler) FocusedMonitorV2(m FocusedMonitorV2) {
+	assert.Equal(h.t, m.MonitorName, "1")
+	assert.Equal(h.t, m.WorkspaceId, 1)
+}
 
-		if _, err := os.Stat(socketPath); err != nil {
-			break
-		}
+func (h *FakeEventHandler) CreateWorkspaceV2(w CreateWorkspaceV2) {
+	assert.Equal(h.t, w.WorkspaceId, 1)
+	assert.Equal(h.t, w.WorkspaceName, "1")
+}
+
+func (h *FakeEventHandler) DestroyWorkspaceV2(w DestroyWorkspaceV2) {
+	assert.Equal(h.t, w.WorkspaceId, 1)
+	assert.Equal(h.t, w.WorkspaceName, "1")
+}
+
+func (h *FakeEventHandler) MoveWorkspaceV2(w MoveWorkspaceV2) {
+	assert.Equal(h.t, w.WorkspaceId, 1)
+	assert.Equal(h.t, w.WorkspaceName, "1")
+	assert.Equal(h.t, w.MonitorName, "1")
+}
+
+func (h *FakeEventHandler) RenameWorkspace(w RenameWorkspace) {
+	assert.Equal(h.t, w.WorkspaceId, 1)
+	assert.Equal(h.t, w.NewName, "1")
+}
+
+func (h *FakeEventHandler) ActiveWindowV2(w ActiveWindowV2) {
+	assert.Equal(h.t, w.Address, "80e62df0")
+}
+
+func (h *FakeEventHandler) WindowTitle(w WindowTitle) {
+	assert.Equal(h.t, w.Address, "80e62df0")
+}
+
+func (h *FakeEventHandler) WindowTitleV2(w WindowTitleV2) {
+	assert.Equal(h.t, w.Address, "80e62df0")
+	assert.Equal(h.t, w.Title, "nvim foo, bar")
+}
+
+func (h *FakeEventHandler) Urgent(u Urgent) {
+	assert.Equal(h.t, u.Address, "80e62df0")
+}
+
+func (h *FakeEventHandler) ChangeFloatingMode(c ChangeFloatingMode) {
+	assert.Equal(h.t, c.Address, "80e62df0")
+	assert.Equal(h.t, c.Floating, true)
+}
+
+func (h *FakeEventHandler) Pin(p Pin) {
+	assert.Equal(h.t, p.Address, "80e62df0")
+	assert.Equal(h.t, p.Pinned, true)
+}
+
+func (h *FakeEventHandler) Minimized(m Minimized) {
+	assert.Equal(h.t, m.Address, "80e62df0")
+	assert.Equal(h.t, m.Minimized, true)
+}
+
+func (h *FakeEventHandler) ToggleGroup(g ToggleGroup) {
+	assert.Equal(h.t, g.Grouped, true)
+	assert.DeepEqual(h.t, g.Addresses, []string{"80e62df0", "80e62df1"})
+}
+
+func (h *FakeEventHandler) MoveIntoGroup(m MoveIntoGroup) {
+	assert.Equal(h.t, m.Address, "80e62df0")
+}
+
+func (h *FakeEventHandler) MoveOutOfGroup(m MoveOutOfGroup) {
+	assert.Equal(h.t, m.Address, "80e62df0")
+}
+
+func (h *FakeEventHandler) LockGroups(l LockGroups) {
+	assert.Equal(h.t, l, true)
+}
+
+func (h *FakeEventHandler) IgnoreGroupLock(i IgnoreGroupLock) {
+	assert.Equal(h.t, i, true)
+}
+
+func (h *FakeEventHandler) Custom(c Custom) {
+	assert.Equal(h.t, c, `{"foo": "bar, baz"}`)
+}
+
+func TestEventSchemaSplit(t *testing.T) {
+	tests := []struct {
+		eventType EventType
+		data      string
+		want      []string
+		wantErr   bool
+	}{
+		{EventWorkspace, "1", []string{"1"}, false},
+		{EventWorkspace, "foo,bar", []string{"foo,bar"}, false},
+		{EventActiveWindow, "kitty,vim a, b, c", []string{"kitty", "vim a, b, c"}, false},
+		{EventActiveWindow, "kitty", nil, true},
+		{EventOpenWindow, "80e62df0,2,kitty,title, with, commas", []string{"80e62df0", "2", "kitty", "title, with, commas"}, false},
+		{EventOpenWindow, "80e62df0,2,kitty", nil, true},
+		{EventMoveWorkspace, "name, with comma,DP-1", []string{"name, with comma", "DP-1"}, false},
+		{EventMoveWorkspace, "1", nil, true},
+		{EventMoveWorkspaceV2, "1,name, with comma,DP-1", []string{"1", "name, with comma", "DP-1"}, false},
+		{EventMoveWorkspaceV2, "1,DP-1", nil, true},
+		{EventToggleGroup, "1,80e62df0,80e62df1", []string{"1", "80e62df0,80e62df1"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.eventType)+">>"+tt.data, func(t *testing.T) {
+			got, err := eventSchemas[tt.eventType].split(tt.data)
+			assert.DeepEqual(t, got, tt.want)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestProcessEventMalformed(t *testing.T) {
+	tests := []ReceivedData{
+		{Type: EventWorkspaceV2, Data: "foo,1"},
+		{Type: EventActiveWindow, Data: "kitty"},
+		{Type: EventOpenWindow, Data: "80e62df0"},
+		{Type: EventScreencast, Data: "1"},
 	}
+	for _, tt := range tests {
+		t.Run(string(tt.Type)+">>"+string(tt.Data), func(t *testing.T) {
+			err := processEvent(&DefaultEventHandler{}, tt, AllEvents)
+			assert.Error(t, err)
+			assert.True(t, errors.Is(err, ErrMalformedEvent))
 
-	c := assert.Must1(NewClient(socketPath))
+			// Events that we are not subscribed are ignored
+			err = processEvent(&DefaultEventHandler{}, tt, nil)
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func BenchmarkReceive(b *testing.B) {
+	s := hyprlandtest.NewEventServer(b)
+
+	c := assert.Must1(NewClient(s.Socket))
 	defer c.Close()
 
+	conn := s.Accept(time.Second)
+	if conn == nil {
+		b.Fatal("client did not connect")
+	}
+
+	go func() {
+		for {
+			// Write messages in bulk, otherwise the server is the
+			// bottleneck
+			var sb strings.Builder
+			for i := 0; i < 256; i++ {
+				sb.WriteString(">>>")
+				sb.Write(RandomBytes(16))
+			}
+
+			if err := conn.Replay(hyprlandtest.Step{Data: sb.String()}); err != nil {
+				return
+			}
+		}
+	}()
+
 	ctx := context.Background()
 
 	// Reset setup time
@@ -309,41 +1039,3 @@ func RandomBytes(n int) []byte {
 
 	return b
 }
-
-func RandomStringServer() {
-	// Remove the previous socket file if it exists
-	if err := os.RemoveAll(socketPath); err != nil {
-		panic(err)
-	}
-
-	listener, err := net.Listen("unix", socketPath)
-	if err != nil {
-		panic(err)
-	}
-	defer listener.Close()
-
-	for {
-		conn, err := listener.Accept()
-		if err != nil {
-			panic(err)
-		}
-
-		writer := bufio.NewWriter(conn)
-
-		go func(c net.Conn) {
-			defer c.Close()
-
-			for {
-				prefix := []byte(">>>")
-				randomData := RandomBytes(16)
-				message := append(prefix, randomData...)
-
-				// Send the message to the client
-				_, err := writer.Write(message)
-				if err != nil {
-					return
-				}
-			}
-		}(conn)
-	}
-}
@@ -3,11 +3,66 @@ package event
 import (
 	"context"
 	"net"
+	"sync"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go/dispatcher"
 )
 
 // EventClient is the event struct from hyprland-go.
 type EventClient struct {
 	conn net.Conn
+	// Socket used to create the client, used as fallback when reconnecting
+	socket string
+	// Resolves the socket again when reconnecting, only set by MustClient
+	resolve func() (string, error)
+	// Data received that is not a complete line yet
+	pending []byte
+	// Non-nil if the client should reconnect, see [WithReconnect]
+	reconnect *ReconnectOptions
+	// Called for events that can not be parsed, see [WithErrorHandler]
+	onError func(err error)
+
+	mu     sync.Mutex
+	err    error
+	closed bool
+}
+
+// ClientOption is an option that can be passed to [NewClient] or
+// [MustClient] to customise the client.
+type ClientOption func(*EventClient)
+
+// ReconnectOptions are the options used by [WithReconnect].
+type ReconnectOptions struct {
+	// Minimum time to wait before trying to reconnect, doubled after
+	// each failed attempt. Defaults to 100ms if <= 0.
+	MinBackoff time.Duration
+	// Maximum time to wait between reconnect attempts. Defaults to 5s if
+	// <= 0.
+	MaxBackoff time.Duration
+	// Called once the connection is lost, with the error that caused it.
+	OnDisconnect func(err error)
+	// Called once the client is connected again.
+	OnReconnect func()
+}
+
+// UnknownHandler can be optionally implemented by an [EventHandler] to handle
+// events not supported by this library, see [Unknown].
+type UnknownHandler interface {
+	// Unknown emitted when an unknown event is received, with the type and
+	// unparsed data.
+	Unknown(u Unknown)
+}
+
+// ConnectionHandler can be optionally implemented by an [EventHandler] to be
+// notified about connection changes when the client was created with
+// [WithReconnect]. Since the events emitted while disconnected are lost, this
+// can be used to resync the state, e.g.: using the hyprland.RequestClient.
+type ConnectionHandler interface {
+	// Disconnected is called when the connection to Hyprland is lost.
+	Disconnected(err error)
+	// Reconnected is called once the connection to Hyprland is restored.
+	Reconnected()
 }
 
 // Event Client interface, right now only used for testing.
@@ -37,6 +92,8 @@ type EventHandler interface {
 	// ActiveWindow emitted on the active window being changed.
 	ActiveWindow(w ActiveWindow)
 	// Fullscreen emitted when a fullscreen status of a window changes.
+	// Hyprland does not include the window address in this event, it
+	// always refers to the active window (see [ActiveWindowV2]).
 	Fullscreen(f Fullscreen)
 	// MonitorRemoved emitted when a monitor is removed (disconnected).
 	MonitorRemoved(m MonitorName)
@@ -66,6 +123,55 @@ type EventHandler interface {
 	// Screencast is fired when the screencopy state of a client changes.
 	// Keep in mind there might be multiple separate clients.
 	Screencast(s Screencast)
+	// WorkspaceV2 emitted on workspace change, same as [Workspace] but
+	// also includes the workspace ID.
+	WorkspaceV2(w WorkspaceV2)
+	// FocusedMonitorV2 emitted on the active monitor being changed, same
+	// as [FocusedMonitor] but includes the workspace ID instead of name.
+	FocusedMonitorV2(m FocusedMonitorV2)
+	// CreateWorkspaceV2 emitted when a workspace is created, same as
+	// [CreateWorkspace] but also includes the workspace ID.
+	CreateWorkspaceV2(w CreateWorkspaceV2)
+	// DestroyWorkspaceV2 emitted when a workspace is destroyed, same as
+	// [DestroyWorkspace] but also includes the workspace ID.
+	DestroyWorkspaceV2(w DestroyWorkspaceV2)
+	// MoveWorkspaceV2 emitted when a workspace is moved to a different
+	// monitor, same as [MoveWorkspace] but also includes the workspace ID.
+	MoveWorkspaceV2(w MoveWorkspaceV2)
+	// RenameWorkspace emitted when a workspace is renamed.
+	RenameWorkspace(w RenameWorkspace)
+	// ActiveWindowV2 emitted on the active window being changed, same as
+	// [ActiveWindow] but includes the window address instead. The address
+	// is empty if no window is focused, e.g.: on an empty workspace.
+	ActiveWindowV2(w ActiveWindowV2)
+	// WindowTitle emitted when a window title changes.
+	WindowTitle(w WindowTitle)
+	// WindowTitleV2 emitted when a window title changes, same as
+	// [WindowTitle] but also includes the new title.
+	WindowTitleV2(w WindowTitleV2)
+	// Urgent emitted when a window requests an urgent state.
+	Urgent(u Urgent)
+	// ChangeFloatingMode emitted when a window changes its floating mode.
+	ChangeFloatingMode(c ChangeFloatingMode)
+	// Pin emitted when a window is pinned or unpinned.
+	Pin(p Pin)
+	// Minimized emitted when an external taskbar-like app requests a
+	// window to be minimized.
+	Minimized(m Minimized)
+	// ToggleGroup emitted when a group is created or destroyed, i.e.: the
+	// togglegroup dispatcher is used.
+	ToggleGroup(t ToggleGroup)
+	// MoveIntoGroup emitted when a window is moved into a group.
+	MoveIntoGroup(m MoveIntoGroup)
+	// MoveOutOfGroup emitted when a window is moved out of a group.
+	MoveOutOfGroup(m MoveOutOfGroup)
+	// LockGroups emitted when lockgroups is toggled.
+	LockGroups(l LockGroups)
+	// IgnoreGroupLock emitted when ignoregrouplock is toggled.
+	IgnoreGroupLock(i IgnoreGroupLock)
+	// Custom emitted when the event dispatcher is called, e.g.: using
+	// hyprland.RequestClient.CustomEvent.
+	Custom(c Custom)
 }
 
 const (
@@ -86,6 +192,34 @@ const (
 	EventCloseLayer       EventType = "closelayer"
 	EventSubMap           EventType = "submap"
 	EventScreencast       EventType = "screencast"
+
+	EventWorkspaceV2        EventType = "workspacev2"
+	EventFocusedMonitorV2   EventType = "focusedmonv2"
+	EventCreateWorkspaceV2  EventType = "createworkspacev2"
+	EventDestroyWorkspaceV2 EventType = "destroyworkspacev2"
+	EventMoveWorkspaceV2    EventType = "moveworkspacev2"
+	EventRenameWorkspace    EventType = "renameworkspace"
+
+	EventActiveWindowV2     EventType = "activewindowv2"
+	EventWindowTitle        EventType = "windowtitle"
+	EventWindowTitleV2      EventType = "windowtitlev2"
+	EventUrgent             EventType = "urgent"
+	EventChangeFloatingMode EventType = "changefloatingmode"
+	EventPin                EventType = "pin"
+	EventMinimized          EventType = "minimized"
+
+	EventToggleGroup     EventType = "togglegroup"
+	EventMoveIntoGroup   EventType = "moveintogroup"
+	EventMoveOutOfGroup  EventType = "moveoutofgroup"
+	EventLockGroups      EventType = "lockgroups"
+	EventIgnoreGroupLock EventType = "ignoregrouplock"
+
+	EventCustom EventType = "custom"
+
+	// EventWildcard can be used instead of the event types to subscribe
+	// to all events, including the ones unknown by this library (see
+	// [Unknown]).
+	EventWildcard EventType = "*"
 )
 
 // AllEvents is the combination of all event types, useful if you want to
@@ -110,6 +244,110 @@ var AllEvents = []EventType{
 	EventCloseLayer,
 	EventSubMap,
 	EventScreencast,
+	EventWorkspaceV2,
+	EventFocusedMonitorV2,
+	EventCreateWorkspaceV2,
+	EventDestroyWorkspaceV2,
+	EventMoveWorkspaceV2,
+	EventRenameWorkspace,
+	EventActiveWindowV2,
+	EventWindowTitle,
+	EventWindowTitleV2,
+	EventUrgent,
+	EventChangeFloatingMode,
+	EventPin,
+	EventMinimized,
+	EventToggleGroup,
+	EventMoveIntoGroup,
+	EventMoveOutOfGroup,
+	EventLockGroups,
+	EventIgnoreGroupLock,
+	EventCustom,
+}
+
+// Describes the payload of an event: the number of comma separated fields,
+// and which field takes the rest of the line (so it may contain commas). By
+// default it is the last field.
+type eventSchema struct {
+	fields int
+	rest   int
+	// Events without payload are ignored, unless the payload is optional
+	optional bool
+}
+
+func fields(n int) eventSchema {
+	return eventSchema{fields: n, rest: n - 1}
+}
+
+// Schema for all supported events, see
+// https://wiki.hyprland.org/IPC/#events-list.
+var eventSchemas = map[EventType]eventSchema{
+	EventWorkspace:        fields(1), // WORKSPACENAME
+	EventFocusedMonitor:   fields(2), // MONNAME,WORKSPACENAME
+	EventActiveWindow:     fields(2), // WINDOWCLASS,WINDOWTITLE
+	EventFullscreen:       fields(1), // 0/1
+	EventMonitorRemoved:   fields(1), // MONITORNAME
+	EventMonitorAdded:     fields(1), // MONITORNAME
+	EventCreateWorkspace:  fields(1), // WORKSPACENAME
+	EventDestroyWorkspace: fields(1), // WORKSPACENAME
+	// WORKSPACENAME,MONNAME
+	EventMoveWorkspace: {fields: 2, rest: 0},
+	EventActiveLayout:  fields(2), // KEYBOARDNAME,LAYOUTNAME
+	EventOpenWindow:    fields(4), // WINDOWADDRESS,WORKSPACENAME,WINDOWCLASS,WINDOWTITLE
+	EventCloseWindow:   fields(1), // WINDOWADDRESS
+	EventMoveWindow:    fields(2), // WINDOWADDRESS,WORKSPACENAME
+	EventOpenLayer:     fields(1), // NAMESPACE
+	EventCloseLayer:    fields(1), // NAMESPACE
+	EventSubMap:        fields(1), // SUBMAPNAME
+	EventScreencast:    fields(2), // STATE,OWNER
+
+	EventWorkspaceV2:        fields(2), // WORKSPACEID,WORKSPACENAME
+	EventFocusedMonitorV2:   fields(2), // MONNAME,WORKSPACEID
+	EventCreateWorkspaceV2:  fields(2), // WORKSPACEID,WORKSPACENAME
+	EventDestroyWorkspaceV2: fields(2), // WORKSPACEID,WORKSPACENAME
+	// WORKSPACEID,WORKSPACENAME,MONNAME
+	EventMoveWorkspaceV2: {fields: 3, rest: 1},
+	EventRenameWorkspace: fields(2), // WORKSPACEID,NEWNAME
+
+	// WINDOWADDRESS, empty when focusing an empty workspace
+	EventActiveWindowV2:     {fields: 1, rest: 0, optional: true},
+	EventWindowTitle:        fields(1), // WINDOWADDRESS
+	EventWindowTitleV2:      fields(2), // WINDOWADDRESS,WINDOWTITLE
+	EventUrgent:             fields(1), // WINDOWADDRESS
+	EventChangeFloatingMode: fields(2), // WINDOWADDRESS,FLOATING
+	EventPin:                fields(2), // WINDOWADDRESS,PINSTATE
+	EventMinimized:          fields(2), // WINDOWADDRESS,MINIMIZED
+
+	EventToggleGroup:     fields(2), // STATE,WINDOWADDRESS(ES)
+	EventMoveIntoGroup:   fields(1), // WINDOWADDRESS
+	EventMoveOutOfGroup:  fields(1), // WINDOWADDRESS
+	EventLockGroups:      fields(1), // 0/1
+	EventIgnoreGroupLock: fields(1), // 0/1
+
+	EventCustom: fields(1), // DATA
+}
+
+// The types below are used only by [EventClient.Events], since
+// [EventHandler] receives the embedded types directly.
+
+type Workspace struct {
+	WorkspaceName
+}
+
+type CreateWorkspace struct {
+	WorkspaceName
+}
+
+type DestroyWorkspace struct {
+	WorkspaceName
+}
+
+type MonitorAdded struct {
+	MonitorName
+}
+
+type MonitorRemoved struct {
+	MonitorName
 }
 
 type MoveWorkspace struct {
@@ -128,6 +366,41 @@ type FocusedMonitor struct {
 
 type WorkspaceName string
 
+// WorkspaceId is the same ID returned in the Id field from
+// hyprland.RequestClient.Workspaces().
+type WorkspaceId int
+
+type WorkspaceV2 struct {
+	WorkspaceId
+	WorkspaceName
+}
+
+type FocusedMonitorV2 struct {
+	MonitorName
+	WorkspaceId
+}
+
+type CreateWorkspaceV2 struct {
+	WorkspaceId
+	WorkspaceName
+}
+
+type DestroyWorkspaceV2 struct {
+	WorkspaceId
+	WorkspaceName
+}
+
+type MoveWorkspaceV2 struct {
+	WorkspaceId
+	WorkspaceName
+	MonitorName
+}
+
+type RenameWorkspace struct {
+	WorkspaceId
+	NewName WorkspaceName
+}
+
 type SubMap string
 
 type CloseLayer string
@@ -148,6 +421,12 @@ type OpenWindow struct {
 	WorkspaceName
 }
 
+// Selector returns a [dispatcher.WindowSelector] matching this window by its
+// address, that can be used in dispatchers and window rules.
+func (o OpenWindow) Selector() dispatcher.WindowSelector {
+	return dispatcher.WindowAddress(o.Address)
+}
+
 type ActiveLayout struct {
 	Type, Name string
 }
@@ -156,6 +435,68 @@ type ActiveWindow struct {
 	Name, Title string
 }
 
+type ActiveWindowV2 struct {
+	Address string
+}
+
+type WindowTitle struct {
+	Address string
+}
+
+type WindowTitleV2 struct {
+	Address, Title string
+}
+
+type Urgent struct {
+	Address string
+}
+
+type ChangeFloatingMode struct {
+	Address string
+	// True if the window is floating, false if tiled.
+	Floating bool
+}
+
+type Pin struct {
+	Address string
+	Pinned  bool
+}
+
+type Minimized struct {
+	Address   string
+	Minimized bool
+}
+
+type ToggleGroup struct {
+	// True if the group was created, false if it was destroyed.
+	Grouped bool
+	// Addresses of the windows in the group.
+	Addresses []string
+}
+
+type MoveIntoGroup struct {
+	Address string
+}
+
+type MoveOutOfGroup struct {
+	Address string
+}
+
+type LockGroups bool
+
+type IgnoreGroupLock bool
+
+// Custom is the data passed to the event dispatcher, as is.
+type Custom string
+
+// Unknown is an event that is not supported by this library, e.g.: events
+// added in newer Hyprland versions or emitted by plugins. It is only received
+// if subscribed explicitly by its type or using [EventWildcard].
+type Unknown struct {
+	Type EventType
+	Data RawData
+}
+
 type ActiveWorkspace WorkspaceName
 
 type Screencast struct {
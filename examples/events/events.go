@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/thiagokokada/hyprland-go"
 	"github.com/thiagokokada/hyprland-go/event"
 )
 
@@ -23,6 +24,10 @@ func (e *ev) Workspace(w event.WorkspaceName) {
 	fmt.Printf("Workspace: %+v\n", w)
 }
 
+func (e *ev) WorkspaceV2(w event.WorkspaceV2) {
+	fmt.Printf("WorkspaceV2: %+v\n", w)
+}
+
 func (e *ev) ActiveWindow(w event.ActiveWindow) {
 	fmt.Printf("ActiveWindow: %+v\n", w)
 }
@@ -37,11 +42,17 @@ func main() {
 	c := event.MustClient()
 	defer must(c.Close())
 
+	// Prefer the events with workspace IDs if Hyprland supports them
+	workspaceEvent := event.EventWorkspace
+	if ok, err := hyprland.MustClient().Supports(hyprland.CapWorkspaceV2Events); err == nil && ok {
+		workspaceEvent = event.EventWorkspaceV2
+	}
+
 	// Will listen for events for 5 seconds and exit
 	must(c.Subscribe(
 		ctx,
 		&ev{},
-		event.EventWorkspace,
+		workspaceEvent,
 		event.EventActiveWindow,
 	))
 
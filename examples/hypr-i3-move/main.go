@@ -8,6 +8,7 @@ import (
 	"os"
 
 	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/dispatcher"
 )
 
 func must1[T any](v T, err error) T {
@@ -27,7 +28,7 @@ func main() {
 		os.Exit(1)
 	}
 	mode := os.Args[1]
-	direction := os.Args[2]
+	direction := dispatcher.Direction(os.Args[2])
 	client := hyprland.MustClient()
 
 	aWindow := must1(client.ActiveWindow())
@@ -37,22 +38,22 @@ func main() {
 	switch mode {
 	case "focus":
 		if len(grouped) == 0 {
-			client.Dispatch(fmt.Sprintf("movefocus %s", direction))
+			client.DispatchCommands(dispatcher.MoveFocus(direction))
 			return
 		}
 
 		switch direction {
-		case "l", "u":
+		case dispatcher.Left, dispatcher.Up:
 			if addr == grouped[0] {
-				client.Dispatch(fmt.Sprintf("movefocus %s", direction))
+				client.DispatchCommands(dispatcher.MoveFocus(direction))
 			} else {
-				client.Dispatch("changegroupactive b")
+				client.DispatchCommands(dispatcher.ChangeGroupActive(dispatcher.Back))
 			}
-		case "r", "d":
+		case dispatcher.Right, dispatcher.Down:
 			if addr == grouped[len(grouped)-1] {
-				client.Dispatch(fmt.Sprintf("movefocus %s", direction))
+				client.DispatchCommands(dispatcher.MoveFocus(direction))
 			} else {
-				client.Dispatch("changegroupactive f")
+				client.DispatchCommands(dispatcher.ChangeGroupActive(dispatcher.Forward))
 			}
 		default:
 			log.Printf("Unknown direction '%s'. Valid options are: l, r, u, d.", direction)
@@ -60,21 +61,21 @@ func main() {
 		}
 	case "move":
 		if len(grouped) == 0 {
-			client.Dispatch(fmt.Sprintf("movewindoworgroup %s", direction))
+			client.DispatchCommands(dispatcher.MoveWindowOrGroup(direction))
 			return
 		}
 		switch direction {
-		case "l", "u":
+		case dispatcher.Left, dispatcher.Up:
 			if addr == grouped[0] {
-				client.Dispatch(fmt.Sprintf("movewindoworgroup %s", direction))
+				client.DispatchCommands(dispatcher.MoveWindowOrGroup(direction))
 			} else {
-				client.Dispatch("movegroupwindow b")
+				client.DispatchCommands(dispatcher.MoveGroupWindow(dispatcher.Back))
 			}
-		case "r", "d":
+		case dispatcher.Right, dispatcher.Down:
 			if addr == grouped[len(grouped)-1] {
-				client.Dispatch(fmt.Sprintf("movewindoworgroup %s", direction))
+				client.DispatchCommands(dispatcher.MoveWindowOrGroup(direction))
 			} else {
-				client.Dispatch("movegroupwindow f")
+				client.DispatchCommands(dispatcher.MoveGroupWindow(dispatcher.Forward))
 			}
 		default:
 			log.Printf("Unknown direction '%s'. Valid options are: l, r, u, d.", direction)
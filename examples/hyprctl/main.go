@@ -9,9 +9,9 @@ import (
 	"io"
 	"os"
 	"sort"
-	"strings"
 
 	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/helpers"
 )
 
 var (
@@ -52,7 +52,7 @@ func mustMarshalIndent(v any) []byte {
 
 func usage(m map[string]func(args []string)) {
 	must1(fmt.Fprintf(out, "Usage of %s:\n", os.Args[0]))
-	must1(fmt.Fprintf(out, "  %s [subcommand] <options>\n\n", os.Args[0]))
+	must1(fmt.Fprintf(out, "  %s [-i instance] [subcommand] <options>\n\n", os.Args[0]))
 	must1(fmt.Fprintf(out, "Available subcommands:\n"))
 
 	// Sort keys before printing, since Go randomises order
@@ -66,9 +66,15 @@ func usage(m map[string]func(args []string)) {
 	for _, s := range subcommands {
 		must1(fmt.Fprintf(out, "  - %s\n", s))
 	}
+
+	must1(fmt.Fprintf(out, "\nOptions:\n"))
+	flag.PrintDefaults()
 }
 
 func main() {
+	instance := flag.String("i", "", "Instance signature or index (see 'instances') to connect to. "+
+		"Defaults to HYPRLAND_INSTANCE_SIGNATURE")
+
 	batchFS := flag.NewFlagSet("batch", flag.ExitOnError)
 	var batch arrayFlags
 	batchFS.Var(&batch, "c", "Command to batch, can be passed multiple times. "+
@@ -100,13 +106,10 @@ func main() {
 				must1(fmt.Fprintf(out, "Error: at least one '-c' is required for batch.\n"))
 				os.Exit(1)
 			} else {
-				// Batch commands are done in the following way:
-				// `[[BATCH]]command0 param0 param1; command1 param0 param1;`
-				r := hyprland.RawRequest(
-					fmt.Sprintf("[[BATCH]]%s", strings.Join(batch, ";")),
-				)
-				v := must1(c.RawRequest(r))
-				must1(fmt.Printf("%s\n", v))
+				v := must1(c.DoBatch(hyprland.NewBatch().Raw(batch...)))
+				for _, r := range v {
+					must1(fmt.Printf("%s: %s\n", r.Command, r.Response))
+				}
 			}
 		},
 		"dispatch": func(args []string) {
@@ -119,6 +122,10 @@ func main() {
 				must1(fmt.Printf("%s\n", v))
 			}
 		},
+		"instances": func(_ []string) {
+			v := must1(helpers.Instances())
+			must1(fmt.Printf("%s\n", mustMarshalIndent(v)))
+		},
 		"kill": func(_ []string) {
 			v := must1(c.Kill())
 			must1(fmt.Printf("%s\n", v))
@@ -127,8 +134,8 @@ func main() {
 			v := must1(c.Reload())
 			must1(fmt.Printf("%s\n", v))
 		},
-		"setcursor": func(_ []string) {
-			must(setcursorFS.Parse(os.Args[2:]))
+		"setcursor": func(args []string) {
+			must(setcursorFS.Parse(args))
 			v := must1(c.SetCursor(*theme, *size))
 			must1(fmt.Printf("%s\n", v))
 		},
@@ -141,15 +148,22 @@ func main() {
 	flag.Usage = func() { usage(m) }
 	flag.Parse()
 
-	if len(os.Args) < 2 {
+	args := flag.Args()
+	if len(args) < 1 {
 		flag.Usage()
 		os.Exit(1)
 	}
 
-	subcommand := os.Args[1]
+	subcommand := args[0]
 	if run, ok := m[subcommand]; ok {
-		c = hyprland.MustClient()
-		run(os.Args[2:])
+		switch {
+		case *instance != "":
+			c = hyprland.NewClientForInstance(must1(helpers.FindInstance(*instance)))
+		case subcommand != "instances":
+			// instances does not need a running Hyprland
+			c = hyprland.MustClient()
+		}
+		run(args[1:])
 	} else {
 		must1(fmt.Fprintf(out, "Error: unknown subcommand: %s\n", subcommand))
 		os.Exit(1)
@@ -7,9 +7,8 @@
 package main
 
 import (
-	"fmt"
-
 	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/dispatcher"
 )
 
 func must1[T any](v T, err error) T {
@@ -28,27 +27,27 @@ func main() {
 
 	aWindow := must1(client.ActiveWindow())
 	if len(aWindow.Grouped) > 0 {
-		must1(client.Dispatch(
+		must1(client.DispatchCommands(
 			// If we are already in a group, ungroup
-			"togglegroup",
+			dispatcher.ToggleGroup(),
 			// Make the current window as master (when using master layout)
-			"layoutmsg swapwithmaster master",
+			dispatcher.LayoutMsg("swapwithmaster master"),
 		))
 	} else {
-		var cmdbuf []string
+		var cmdbuf []dispatcher.Dispatcher
 		aWorkspace := must1(client.ActiveWorkspace())
 		clients := must1(client.Clients())
 
 		// Grab all windows in the active workspace
-		var windows []string
+		var windows []dispatcher.WindowSelector
 		for _, c := range clients {
 			if c.Workspace.Id == aWorkspace.Id {
-				windows = append(windows, c.Address)
+				windows = append(windows, c.Selector())
 			}
 		}
 
 		// Start by creating a new group
-		cmdbuf = append(cmdbuf, "togglegroup")
+		cmdbuf = append(cmdbuf, dispatcher.ToggleGroup())
 		for _, w := range windows {
 			// Move each window inside the group
 			// Once is not enough in case of very "deep" layouts,
@@ -60,20 +59,20 @@ func main() {
 			// supported moving windows based on address and not
 			// only positions
 			for i := 0; i < 2; i++ {
-				cmdbuf = append(cmdbuf, fmt.Sprintf("focuswindow address:%s", w))
-				cmdbuf = append(cmdbuf, "layoutmsg swapwithmaster auto")
-				cmdbuf = append(cmdbuf, "moveintogroup l")
-				cmdbuf = append(cmdbuf, "moveintogroup r")
-				cmdbuf = append(cmdbuf, "moveintogroup u")
-				cmdbuf = append(cmdbuf, "moveintogroup d")
+				cmdbuf = append(cmdbuf, dispatcher.FocusWindow(w))
+				cmdbuf = append(cmdbuf, dispatcher.LayoutMsg("swapwithmaster auto"))
+				cmdbuf = append(cmdbuf, dispatcher.MoveIntoGroup(dispatcher.Left))
+				cmdbuf = append(cmdbuf, dispatcher.MoveIntoGroup(dispatcher.Right))
+				cmdbuf = append(cmdbuf, dispatcher.MoveIntoGroup(dispatcher.Up))
+				cmdbuf = append(cmdbuf, dispatcher.MoveIntoGroup(dispatcher.Down))
 			}
 		}
 		// Focus in the active window at the end
-		cmdbuf = append(cmdbuf, fmt.Sprintf("focuswindow address:%s", aWindow.Address))
+		cmdbuf = append(cmdbuf, dispatcher.FocusWindow(aWindow.Selector()))
 
 		// Dispatch buffered commands in one call for performance,
 		// hyprland-go will take care of splitting it in smaller calls
 		// if necessary
-		must1(client.Dispatch(cmdbuf...))
+		must1(client.DispatchCommands(cmdbuf...))
 	}
 }
@@ -1,35 +1,204 @@
 package helpers
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 )
 
-// Returned if HYPRLAND_INSTANCE_SIGNATURE is empty.
-var ErrEmptyHis = errors.New("HYPRLAND_INSTANCE_SIGNATURE is empty")
+var (
+	// Returned if HYPRLAND_INSTANCE_SIGNATURE is empty and no running
+	// instance was found.
+	ErrEmptyHis = errors.New("HYPRLAND_INSTANCE_SIGNATURE is empty")
+	// Returned if an instance could not be found, see [FindInstance].
+	ErrInstanceNotFound = errors.New("instance not found")
+)
 
 // Returns a Hyprland socket path.
+// Uses the instance from HYPRLAND_INSTANCE_SIGNATURE, or if it is empty (e.g.:
+// from a systemd service or SSH session), the oldest running instance, like
+// hyprctl does. Before, an empty HYPRLAND_INSTANCE_SIGNATURE always returned
+// [ErrEmptyHis], now it is only returned if no instance is running.
 func GetSocket(socket Socket) (string, error) {
 	his := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
-	if his == "" {
-		return "", fmt.Errorf("%w, are you using Hyprland?", ErrEmptyHis)
+	if his != "" {
+		runtimeDir, err := getRuntimeDir()
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(runtimeDir, "hypr", his, string(socket)), nil
+	}
+
+	instances, _ := Instances()
+	for _, i := range instances {
+		if i.Alive {
+			return i.Socket(socket), nil
+		}
+	}
+
+	return "", fmt.Errorf("%w and no running instance found, are you using Hyprland?", ErrEmptyHis)
+}
+
+// Instances returns the Hyprland instances from the current user, found in
+// '$XDG_RUNTIME_DIR/hypr/', ordered by start time (oldest first). Instances
+// from Hyprland processes that are not running anymore (e.g.: after a crash)
+// are also returned, check [Instance.Alive].
+// Returns an empty list if there is no instance.
+func Instances() ([]Instance, error) {
+	runtimeDir, err := getRuntimeDir()
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Join(runtimeDir, "hypr")
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return []Instance{}, nil
 	}
 
-	// https://github.com/hyprwm/Hyprland/blob/83a5395eaa99fecef777827fff1de486c06b6180/hyprctl/main.cpp#L53-L62
+	if err != nil {
+		return nil, fmt.Errorf("error while reading instances: %w", err)
+	}
+
+	instances := make([]Instance, 0, len(entries))
+
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+
+		i, err := readInstance(filepath.Join(dir, e.Name()))
+		if err != nil {
+			// not an instance, e.g.: missing lock file
+			continue
+		}
+
+		instances = append(instances, i)
+	}
+
+	slices.SortStableFunc(instances, func(a, b Instance) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return instances, nil
+}
+
+// FindInstance returns an instance by its signature or by its index in
+// [Instances], similar to 'hyprctl --instance'.
+func FindInstance(id string) (Instance, error) {
+	instances, err := Instances()
+	if err != nil {
+		return Instance{}, err
+	}
+
+	for _, i := range instances {
+		if i.Signature == id {
+			return i, nil
+		}
+	}
+
+	if n, err := strconv.Atoi(id); err == nil && n >= 0 && n < len(instances) {
+		return instances[n], nil
+	}
+
+	return Instance{}, fmt.Errorf("%w: %s", ErrInstanceNotFound, id)
+}
+
+// Socket returns the path for one of the instance sockets.
+func (i Instance) Socket(socket Socket) string {
+	return filepath.Join(i.Dir, string(socket))
+}
+
+// https://github.com/hyprwm/Hyprland/blob/83a5395eaa99fecef777827fff1de486c06b6180/hyprctl/main.cpp#L53-L62
+func getRuntimeDir() (string, error) {
 	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir != "" {
+		return runtimeDir, nil
+	}
 
 	u, err := user.Current()
 	if err != nil {
 		return "", fmt.Errorf("error while getting the current user: %w", err)
 	}
 
-	if runtimeDir == "" {
-		user := u.Uid
-		runtimeDir = filepath.Join("/run/user", user)
+	return filepath.Join("/run/user", u.Uid), nil
+}
+
+// Read an instance from its directory. The lock file contains the PID in the
+// first line and the Wayland display in the second one.
+func readInstance(dir string) (i Instance, err error) {
+	lock := filepath.Join(dir, LockFile)
+
+	data, err := os.ReadFile(lock)
+	if err != nil {
+		return i, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+
+	if len(lines) < 2 {
+		return i, fmt.Errorf("invalid lock file: %s", lock)
+	}
+
+	pid, err := strconv.Atoi(lines[0])
+	if err != nil {
+		return i, fmt.Errorf("invalid pid in lock file: %s: %w", lock, err)
+	}
+
+	i = Instance{
+		Signature:      filepath.Base(dir),
+		Dir:            dir,
+		Pid:            pid,
+		WaylandDisplay: lines[1],
+		Alive:          isAlive(pid),
+	}
+
+	// The signature has the start time, e.g.: '<commit>_<unix time>_<random>'
+	parts := strings.Split(i.Signature, "_")
+	if len(parts) == 3 {
+		if sec, err := strconv.ParseInt(parts[1], 10, 64); err == nil {
+			i.Time = time.Unix(sec, 0)
+		}
+	}
+
+	if i.Time.IsZero() {
+		if info, err := os.Stat(lock); err == nil {
+			i.Time = info.ModTime()
+		}
 	}
 
-	return filepath.Join(runtimeDir, "hypr", his, string(socket)), nil
+	return i, nil
+}
+
+// Check if a process is running by sending the signal 0 to it.
+func isAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	p, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = p.Signal(syscall.Signal(0))
+
+	// EPERM means that the process is running, but owned by another user
+	return err == nil || errors.Is(err, os.ErrPermission)
 }
@@ -1,8 +1,13 @@
 package helpers
 
 import (
+	"errors"
+	"fmt"
+	"os"
 	"os/user"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/thiagokokada/hyprland-go/internal/assert"
 )
@@ -38,7 +43,88 @@ func TestGetSocketWithoutXdg(t *testing.T) {
 
 func TestGetSocketError(t *testing.T) {
 	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+	// Otherwise a running Hyprland would be found
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
 
 	_, err := GetSocket(RequestSocket)
 	assert.Error(t, err)
 }
+
+func writeInstance(t *testing.T, runtimeDir string, signature string, lock string) {
+	t.Helper()
+
+	dir := filepath.Join(runtimeDir, "hypr", signature)
+	assert.NoError(t, os.MkdirAll(dir, 0o700))
+
+	if lock != "" {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, LockFile), []byte(lock), 0o600))
+	}
+}
+
+func TestInstances(t *testing.T) {
+	runtimeDir := t.TempDir()
+	t.Setenv("XDG_RUNTIME_DIR", runtimeDir)
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+
+	instances, err := Instances()
+	assert.NoError(t, err)
+	assert.Equal(t, len(instances), 0)
+
+	_, err = GetSocket(RequestSocket)
+	assert.True(t, errors.Is(err, ErrEmptyHis))
+
+	pid := os.Getpid()
+	// PIDs are limited to 2^22 in Linux, so this one is never running
+	deadPid := 1 << 30
+
+	writeInstance(t, runtimeDir, "abc_1700000200_1", fmt.Sprintf("%d\nwayland-2\n", pid))
+	writeInstance(t, runtimeDir, "abc_1700000100_1", fmt.Sprintf("%d\nwayland-1\n", deadPid))
+	writeInstance(t, runtimeDir, "abc_1700000300_1", fmt.Sprintf("%d\nwayland-3\n", pid))
+	writeInstance(t, runtimeDir, "nolock", "")
+	writeInstance(t, runtimeDir, "invalid", "foo\n")
+
+	instances, err = Instances()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, instances, []Instance{
+		{
+			Signature:      "abc_1700000100_1",
+			Dir:            filepath.Join(runtimeDir, "hypr", "abc_1700000100_1"),
+			Time:           time.Unix(1700000100, 0),
+			Pid:            deadPid,
+			WaylandDisplay: "wayland-1",
+			Alive:          false,
+		},
+		{
+			Signature:      "abc_1700000200_1",
+			Dir:            filepath.Join(runtimeDir, "hypr", "abc_1700000200_1"),
+			Time:           time.Unix(1700000200, 0),
+			Pid:            pid,
+			WaylandDisplay: "wayland-2",
+			Alive:          true,
+		},
+		{
+			Signature:      "abc_1700000300_1",
+			Dir:            filepath.Join(runtimeDir, "hypr", "abc_1700000300_1"),
+			Time:           time.Unix(1700000300, 0),
+			Pid:            pid,
+			WaylandDisplay: "wayland-3",
+			Alive:          true,
+		},
+	})
+
+	// Without HYPRLAND_INSTANCE_SIGNATURE, the oldest running instance is used
+	socket, err := GetSocket(EventSocket)
+	assert.NoError(t, err)
+	assert.Equal(t, socket, filepath.Join(runtimeDir, "hypr", "abc_1700000200_1", ".socket2.sock"))
+
+	i, err := FindInstance("abc_1700000300_1")
+	assert.NoError(t, err)
+	assert.Equal(t, i.WaylandDisplay, "wayland-3")
+
+	i, err = FindInstance("1")
+	assert.NoError(t, err)
+	assert.Equal(t, i.WaylandDisplay, "wayland-2")
+
+	_, err = FindInstance("3")
+	assert.True(t, errors.Is(err, ErrInstanceNotFound))
+}
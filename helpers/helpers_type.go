@@ -1,8 +1,31 @@
 package helpers
 
+import "time"
+
 type Socket string
 
 const (
 	EventSocket   Socket = ".socket2.sock"
 	RequestSocket Socket = ".socket.sock"
 )
+
+// Lock file created by Hyprland in the instance directory, containing its PID
+// and Wayland display.
+const LockFile = "hyprland.lock"
+
+// Instance is a Hyprland instance found in
+// '$XDG_RUNTIME_DIR/hypr/$HYPRLAND_INSTANCE_SIGNATURE/', see [Instances].
+type Instance struct {
+	// Instance signature, i.e.: HYPRLAND_INSTANCE_SIGNATURE.
+	Signature string
+	// Instance directory, where the sockets are located.
+	Dir string
+	// Time the instance was started.
+	Time time.Time
+	// PID of the Hyprland process.
+	Pid int
+	// Wayland display, i.e.: WAYLAND_DISPLAY, e.g.: "wayland-1".
+	WaylandDisplay string
+	// True if the Hyprland process is running.
+	Alive bool
+}
@@ -0,0 +1,215 @@
+package hyprlandtest
+
+import (
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go/helpers"
+)
+
+// Step is a single step in an event script, see [EventServer.Replay].
+type Step struct {
+	// Raw data to be written to the socket, generally one or more event
+	// lines in the 'TYPE>>DATA\n' format. See [Event].
+	Data string
+	// Time to wait before writing this step.
+	Delay time.Duration
+	// If true, drop the connection instead of writing Data.
+	Drop bool
+}
+
+// Event returns a step that writes a single event line, e.g.:
+// Event("workspace", "1") writes 'workspace>>1\n'.
+func Event(eventType string, data string) Step {
+	return Step{Data: eventType + ">>" + data + "\n"}
+}
+
+// Drop returns a step that drops the connection.
+func Drop() Step {
+	return Step{Drop: true}
+}
+
+// Sleep returns a step that only waits for a duration.
+func Sleep(d time.Duration) Step {
+	return Step{Delay: d}
+}
+
+// EventServer is a fake Hyprland event socket ('.socket2.sock').
+type EventServer struct {
+	// Instance where the socket is located.
+	Instance *Instance
+	// Path of the socket, can be passed to event.NewClient.
+	Socket string
+
+	listener net.Listener
+	conns    chan net.Conn
+	done     chan struct{}
+	wg       sync.WaitGroup
+	once     sync.Once
+
+	mu  sync.Mutex
+	all []net.Conn
+}
+
+// Starts a new fake event socket server in a new [Instance]. The server is
+// closed at the end of the test.
+func NewEventServer(tb testing.TB) *EventServer {
+	tb.Helper()
+
+	return NewInstance(tb).NewEventServer(tb)
+}
+
+// Starts a new fake event socket server in the instance. The server is closed
+// at the end of the test.
+func (i *Instance) NewEventServer(tb testing.TB) *EventServer {
+	tb.Helper()
+
+	s := &EventServer{
+		Instance: i,
+		Socket:   i.Socket(helpers.EventSocket),
+		conns:    make(chan net.Conn),
+		done:     make(chan struct{}),
+	}
+
+	l, err := net.Listen("unix", s.Socket)
+	if err != nil {
+		tb.Fatalf("error while listening to socket: %v", err)
+	}
+
+	s.listener = l
+
+	s.wg.Add(1)
+
+	go s.serve()
+
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// Accept waits for the next client connection, returning nil if no client
+// connected before the timeout.
+func (s *EventServer) Accept(timeout time.Duration) *EventConn {
+	select {
+	case conn := <-s.conns:
+		return &EventConn{conn: conn}
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
+// Close the server and all client connections, e.g.: to simulate Hyprland
+// exiting. It is safe to call Close multiple times.
+func (s *EventServer) Close() {
+	s.once.Do(func() {
+		close(s.done)
+		s.listener.Close()
+		s.wg.Wait()
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		for _, conn := range s.all {
+			conn.Close()
+		}
+	})
+}
+
+func (s *EventServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		s.all = append(s.all, conn)
+		s.mu.Unlock()
+
+		select {
+		case s.conns <- conn:
+		case <-s.done:
+			return
+		}
+	}
+}
+
+// EventConn is a client connected to an [EventServer].
+// The chunk configuration should be set before writing to the connection,
+// e.g.: before starting a goroutine calling [EventConn.Replay].
+type EventConn struct {
+	// Maximum amount of bytes written in each write call, useful to
+	// simulate events split between reads. If <= 0, each step is written
+	// in one call.
+	ChunkSize int
+	// Time to wait between each chunk written.
+	ChunkDelay time.Duration
+
+	conn net.Conn
+}
+
+// Replay writes a script of steps to the client, respecting the delays and
+// the chunk configuration from the connection. Stops at the first error, e.g.:
+// when the client disconnects or after a [Drop] step.
+func (c *EventConn) Replay(steps ...Step) error {
+	for _, step := range steps {
+		time.Sleep(step.Delay)
+
+		if step.Drop {
+			return c.Drop()
+		}
+
+		if err := c.write([]byte(step.Data)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Emit writes one event line for each pair of type and data, e.g.:
+// Emit("workspace", "1", "activewindow", "kitty,fish").
+func (c *EventConn) Emit(pairs ...string) error {
+	if len(pairs)%2 != 0 {
+		panic("odd number of arguments")
+	}
+
+	steps := make([]Step, 0, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		steps = append(steps, Event(pairs[i], pairs[i+1]))
+	}
+
+	return c.Replay(steps...)
+}
+
+// Drop closes the connection with the client.
+func (c *EventConn) Drop() error {
+	return c.conn.Close()
+}
+
+func (c *EventConn) write(data []byte) error {
+	size := c.ChunkSize
+	if size <= 0 {
+		size = len(data)
+	}
+
+	for len(data) > 0 {
+		n := min(size, len(data))
+
+		if _, err := c.conn.Write(data[:n]); err != nil {
+			return err
+		}
+
+		data = data[n:]
+
+		if len(data) > 0 {
+			time.Sleep(c.ChunkDelay)
+		}
+	}
+
+	return nil
+}
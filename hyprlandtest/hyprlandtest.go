@@ -0,0 +1,333 @@
+// Package hyprlandtest provides utilities to test code using hyprland-go
+// without a running Hyprland instance, similar to what [net/http/httptest]
+// does for HTTP.
+package hyprlandtest
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/thiagokokada/hyprland-go/helpers"
+)
+
+const (
+	// Signature used by fake instances.
+	Signature = "hyprlandtest"
+	// Wayland display written in the lock file of fake instances.
+	WaylandDisplay = "wayland-hyprlandtest"
+	// Response returned by Hyprland for unknown commands.
+	UnknownRequest = "unknown request"
+
+	// https://github.com/hyprwm/Hyprland/blob/918d8340afd652b011b937d29d5eea0be08467f5/hyprctl/main.cpp#L278
+	batch = "[[BATCH]]"
+	// https://github.com/hyprwm/Hyprland/blob/918d8340afd652b011b937d29d5eea0be08467f5/hyprctl/main.cpp#L257
+	bufSize = 8192
+)
+
+// Instance is a fake Hyprland instance directory, following the same layout
+// as Hyprland: '$XDG_RUNTIME_DIR/hypr/$HYPRLAND_INSTANCE_SIGNATURE/'.
+type Instance struct {
+	RuntimeDir string
+	Signature  string
+}
+
+// Creates a new fake instance in a temporary directory, that is removed at
+// the end of the test. The instance has a lock file with the PID of the test
+// process, so it is considered running.
+func NewInstance(tb testing.TB) *Instance {
+	tb.Helper()
+
+	// Not using tb.TempDir() since Unix socket paths are limited to ~108
+	// bytes and the test name is part of the path
+	dir, err := os.MkdirTemp("", "hypr")
+	if err != nil {
+		tb.Fatalf("error while creating runtime dir: %v", err)
+	}
+
+	tb.Cleanup(func() { os.RemoveAll(dir) })
+
+	i := &Instance{RuntimeDir: dir, Signature: Signature}
+	if err := os.MkdirAll(i.Dir(), 0o700); err != nil {
+		tb.Fatalf("error while creating instance dir: %v", err)
+	}
+
+	// Lock file pointing to the test process, so the instance is found by
+	// helpers.Instances
+	lock := fmt.Sprintf("%d\n%s\n", os.Getpid(), WaylandDisplay)
+	if err := os.WriteFile(filepath.Join(i.Dir(), helpers.LockFile), []byte(lock), 0o600); err != nil {
+		tb.Fatalf("error while creating lock file: %v", err)
+	}
+
+	return i
+}
+
+// Dir returns the instance directory.
+func (i *Instance) Dir() string {
+	return filepath.Join(i.RuntimeDir, "hypr", i.Signature)
+}
+
+// Socket returns the path for one of the instance sockets.
+func (i *Instance) Socket(socket helpers.Socket) string {
+	return filepath.Join(i.Dir(), string(socket))
+}
+
+// Setenv sets HYPRLAND_INSTANCE_SIGNATURE and XDG_RUNTIME_DIR for the
+// duration of the test, so [helpers.GetSocket] (and functions using it, like
+// MustClient) will resolve to this instance.
+func (i *Instance) Setenv(tb testing.TB) {
+	tb.Helper()
+
+	tb.Setenv("HYPRLAND_INSTANCE_SIGNATURE", i.Signature)
+	tb.Setenv("XDG_RUNTIME_DIR", i.RuntimeDir)
+}
+
+// Command is a single command received by the [Server], e.g.: 'j/monitors
+// all' will be parsed as Command{JSON: true, Name: "monitors", Args: "all"}.
+type Command struct {
+	// Flags passed before the '/', e.g.: 'j'.
+	Flags string
+	// True if JSON output was requested, i.e.: the 'j' flag.
+	JSON bool
+	Name string
+	Args string
+}
+
+// String returns the command without the flags, e.g.: "monitors all".
+func (c Command) String() string {
+	if c.Args == "" {
+		return c.Name
+	}
+
+	return c.Name + " " + c.Args
+}
+
+// Request is a request received by the [Server]. A request may contain
+// multiple commands in case of batch requests.
+type Request struct {
+	Raw      string
+	Batch    bool
+	Commands []Command
+}
+
+// Handler returns the response for a command.
+type Handler func(cmd Command) string
+
+// Server is a fake Hyprland request socket ('.socket.sock').
+type Server struct {
+	// Instance where the socket is located.
+	Instance *Instance
+	// Path of the socket, can be passed to hyprland.NewClient.
+	Socket string
+
+	listener net.Listener
+	wg       sync.WaitGroup
+
+	mu       sync.Mutex
+	handlers map[string]Handler
+	requests []Request
+}
+
+// Starts a new fake request socket server in a new [Instance]. The server is
+// closed at the end of the test.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	return NewInstance(tb).NewServer(tb)
+}
+
+// Starts a new fake request socket server in the instance. The server is
+// closed at the end of the test.
+func (i *Instance) NewServer(tb testing.TB) *Server {
+	tb.Helper()
+
+	s := &Server{
+		Instance: i,
+		Socket:   i.Socket(helpers.RequestSocket),
+		handlers: make(map[string]Handler),
+	}
+
+	l, err := net.Listen("unix", s.Socket)
+	if err != nil {
+		tb.Fatalf("error while listening to socket: %v", err)
+	}
+
+	s.listener = l
+
+	s.wg.Add(1)
+
+	go s.serve()
+
+	tb.Cleanup(s.Close)
+
+	return s
+}
+
+// Handle registers a handler for a command name, e.g.: "dispatch". The
+// handler will be called once for each command in a batch request.
+func (s *Server) Handle(name string, h Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handlers[name] = h
+}
+
+// HandleResponse registers a canned response for a command name, e.g.:
+// HandleResponse("activewindow", `{"address": "0x1234"}`).
+func (s *Server) HandleResponse(name string, response string) {
+	s.Handle(name, func(Command) string { return response })
+}
+
+// HandleScript registers a sequence of responses for a command name, each
+// call returns the next response in the sequence. After the end of the
+// sequence, the last response is repeated.
+func (s *Server) HandleScript(name string, responses ...string) {
+	if len(responses) == 0 {
+		panic("empty script")
+	}
+
+	var (
+		mu sync.Mutex
+		i  int
+	)
+
+	s.Handle(name, func(Command) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		r := responses[min(i, len(responses)-1)]
+		i++
+
+		return r
+	})
+}
+
+// Requests returns all requests received by the server so far, in order.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// Commands returns all commands received by the server so far, in order,
+// flattening batch requests.
+func (s *Server) Commands() []Command {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var cmds []Command
+	for _, r := range s.requests {
+		cmds = append(cmds, r.Commands...)
+	}
+
+	return cmds
+}
+
+// Close the server and wait until all connections are done.
+func (s *Server) Close() {
+	s.listener.Close()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.wg.Add(1)
+
+		go func() {
+			defer s.wg.Done()
+			defer conn.Close()
+
+			s.handleConn(conn)
+		}()
+	}
+}
+
+func (s *Server) handleConn(conn net.Conn) {
+	// Similar to Hyprland, read the request in one go
+	buf := make([]byte, bufSize)
+
+	n, err := conn.Read(buf)
+	if err != nil {
+		return
+	}
+
+	req := ParseRequest(string(buf[:n]))
+
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	var sb strings.Builder
+
+	for _, cmd := range req.Commands {
+		sb.WriteString(s.respond(cmd))
+		// Similar to Hyprland, each batch response is followed by an
+		// empty line
+		if req.Batch {
+			sb.WriteString("\n\n")
+		}
+	}
+
+	// Ignoring error since the client may have gone away
+	_, _ = conn.Write([]byte(sb.String()))
+}
+
+func (s *Server) respond(cmd Command) string {
+	s.mu.Lock()
+	h, ok := s.handlers[cmd.Name]
+	s.mu.Unlock()
+
+	if !ok {
+		return UnknownRequest
+	}
+
+	return h(cmd)
+}
+
+// ParseRequest parses a raw request in the same format Hyprland accepts,
+// e.g.: '[[BATCH]]j/clients;dispatch exec kitty;'.
+func ParseRequest(raw string) Request {
+	req := Request{Raw: raw}
+
+	if rest, ok := strings.CutPrefix(raw, batch); ok {
+		req.Batch = true
+
+		for _, c := range strings.Split(rest, ";") {
+			if c = strings.TrimSpace(c); c != "" {
+				req.Commands = append(req.Commands, parseCommand(c))
+			}
+		}
+	} else {
+		req.Commands = []Command{parseCommand(strings.TrimSpace(raw))}
+	}
+
+	return req
+}
+
+func parseCommand(raw string) Command {
+	var cmd Command
+
+	// Flags are in the format 'flags/command', e.g.: 'j/clients'
+	if flags, rest, ok := strings.Cut(raw, "/"); ok && !strings.Contains(flags, " ") {
+		cmd.Flags = flags
+		cmd.JSON = strings.Contains(flags, "j")
+		raw = rest
+	}
+
+	cmd.Name, cmd.Args, _ = strings.Cut(raw, " ")
+	cmd.Args = strings.TrimSpace(cmd.Args)
+
+	return cmd
+}
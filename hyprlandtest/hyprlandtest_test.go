@@ -0,0 +1,117 @@
+package hyprlandtest
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/helpers"
+	"github.com/thiagokokada/hyprland-go/internal/assert"
+)
+
+func TestParseRequest(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Request
+	}{
+		{"splash", Request{
+			Raw:      "splash",
+			Commands: []Command{{Name: "splash"}},
+		}},
+		{"j/monitors all", Request{
+			Raw:      "j/monitors all",
+			Commands: []Command{{Flags: "j", JSON: true, Name: "monitors", Args: "all"}},
+		}},
+		{"dispatch exec kitty sh -c 'a/b'", Request{
+			Raw:      "dispatch exec kitty sh -c 'a/b'",
+			Commands: []Command{{Name: "dispatch", Args: "exec kitty sh -c 'a/b'"}},
+		}},
+		{"[[BATCH]]j/clients ;dispatch exec kitty;", Request{
+			Raw:   "[[BATCH]]j/clients ;dispatch exec kitty;",
+			Batch: true,
+			Commands: []Command{
+				{Flags: "j", JSON: true, Name: "clients"},
+				{Name: "dispatch", Args: "exec kitty"},
+			},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("tests_%s", tt.raw), func(t *testing.T) {
+			assert.DeepEqual(t, ParseRequest(tt.raw), tt.want)
+		})
+	}
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer(t)
+	s.HandleResponse("activewindow", `{"address": "0x1234", "title": "kitty"}`)
+	s.HandleScript("dispatch", "ok", "Invalid dispatcher")
+
+	c := hyprland.NewClient(s.Socket)
+
+	w, err := c.ActiveWindow()
+	assert.NoError(t, err)
+	assert.Equal(t, w.Address, "0x1234")
+	assert.Equal(t, w.Title, "kitty")
+
+	r, err := c.Dispatch("exec kitty", "exec foo")
+	assert.Error(t, err)
+	assert.DeepEqual(t, r, []hyprland.Response{"ok", "Invalid dispatcher"})
+
+	_, err = c.Splash()
+	assert.NoError(t, err)
+
+	assert.Equal(t, len(s.Requests()), 3)
+	assert.DeepEqual(t, s.Commands(), []Command{
+		{Flags: "j", JSON: true, Name: "activewindow"},
+		{Name: "dispatch", Args: "exec kitty"},
+		{Name: "dispatch", Args: "exec foo"},
+		{Name: "splash"},
+	})
+}
+
+func TestServerSetenv(t *testing.T) {
+	s := NewServer(t)
+	s.HandleResponse("splash", "Hello from hyprlandtest!")
+	s.Instance.Setenv(t)
+
+	socket, err := helpers.GetSocket(helpers.RequestSocket)
+	assert.NoError(t, err)
+	assert.Equal(t, socket, s.Socket)
+
+	splash, err := hyprland.MustClient().Splash()
+	assert.NoError(t, err)
+	assert.Equal(t, splash, "Hello from hyprlandtest!")
+}
+
+func TestEventServer(t *testing.T) {
+	s := NewEventServer(t)
+
+	conn, err := net.Dial("unix", s.Socket)
+	assert.NoError(t, err)
+
+	defer conn.Close()
+
+	c := s.Accept(time.Second)
+	if c == nil {
+		t.Fatal("client did not connect")
+	}
+
+	c.ChunkSize = 3
+
+	go func() {
+		c.Replay(
+			Event("workspace", "1"),
+			Step{Data: "activewindow>>kitty,fish\n", Delay: 10 * time.Millisecond},
+			Drop(),
+			Event("workspace", "2"),
+		)
+	}()
+
+	got, err := io.ReadAll(conn)
+	assert.NoError(t, err)
+	assert.Equal(t, string(got), "workspace>>1\nactivewindow>>kitty,fish\n")
+}
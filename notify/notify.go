@@ -0,0 +1,151 @@
+// Package notify implements a queue for Hyprland notifications (see
+// hyprland.RequestClient.Notify) that rate-limits bursts, e.g.: from busy
+// daemons, so they do not flood the screen.
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+)
+
+const (
+	// Default interval between notifications after a burst, see
+	// [WithInterval].
+	DefaultInterval = time.Second
+	// Default number of notifications sent at once, see [WithBurst].
+	DefaultBurst = 3
+	// Default number of notifications waiting to be sent, see
+	// [WithMaxPending].
+	DefaultMaxPending = 10
+)
+
+// Initiate a new queue.
+// Receives a client used to send the notifications. Notifications are only
+// sent while [Queue.Run] is running.
+// Optionally receives a list of [Option] to customise the queue.
+func New(client *hyprland.RequestClient, opts ...Option) *Queue {
+	q := &Queue{
+		client:     client,
+		interval:   DefaultInterval,
+		burst:      DefaultBurst,
+		maxPending: DefaultMaxPending,
+		wake:       make(chan struct{}, 1),
+	}
+
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q
+}
+
+// WithInterval sets the interval between notifications after a burst. An
+// interval <= 0 is ignored.
+func WithInterval(interval time.Duration) Option {
+	return func(q *Queue) {
+		if interval > 0 {
+			q.interval = interval
+		}
+	}
+}
+
+// WithBurst sets the number of notifications that can be sent at once. A
+// burst <= 0 is ignored.
+func WithBurst(burst int) Option {
+	return func(q *Queue) {
+		if burst > 0 {
+			q.burst = burst
+		}
+	}
+}
+
+// WithMaxPending sets the number of notifications waiting to be sent. When
+// the queue is full, the oldest notification is dropped, since newer ones are
+// generally more relevant. A maxPending <= 0 is ignored.
+func WithMaxPending(maxPending int) Option {
+	return func(q *Queue) {
+		if maxPending > 0 {
+			q.maxPending = maxPending
+		}
+	}
+}
+
+// Push adds a notification to the queue, without blocking. If the queue is
+// full, the oldest pending notification is dropped, see [Queue.Dropped].
+func (q *Queue) Push(n hyprland.Notification) {
+	q.mu.Lock()
+	q.pending = append(q.pending, n)
+	if len(q.pending) > q.maxPending {
+		q.pending = q.pending[len(q.pending)-q.maxPending:]
+		q.dropped++
+	}
+	q.mu.Unlock()
+
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Len returns the number of notifications waiting to be sent.
+func (q *Queue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return len(q.pending)
+}
+
+// Dropped returns the number of notifications dropped because the queue was
+// full.
+func (q *Queue) Dropped() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.dropped
+}
+
+// Run sends the pending notifications until the context is done, returning
+// the context error. Errors while sending are ignored, since notifications
+// are not critical and Hyprland may be restarting.
+func (q *Queue) Run(ctx context.Context) error {
+	ticker := time.NewTicker(q.interval)
+	defer ticker.Stop()
+
+	tokens := q.burst
+
+	for {
+		for tokens > 0 {
+			n, ok := q.pop()
+			if !ok {
+				break
+			}
+
+			_, _ = q.client.NotifyWithContext(ctx, n)
+			tokens--
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			tokens = min(tokens+1, q.burst)
+		case <-q.wake:
+		}
+	}
+}
+
+func (q *Queue) pop() (n hyprland.Notification, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if len(q.pending) == 0 {
+		return n, false
+	}
+
+	n = q.pending[0]
+	q.pending = q.pending[1:]
+
+	return n, true
+}
@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/hyprlandtest"
+	"github.com/thiagokokada/hyprland-go/internal/assert"
+)
+
+func TestQueue(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	s.HandleResponse("notify", "ok")
+
+	interval := 100 * time.Millisecond
+	q := New(
+		hyprland.NewClient(s.Socket),
+		WithInterval(interval),
+		WithBurst(2),
+		WithMaxPending(3),
+	)
+
+	for _, m := range []string{"1", "2", "3", "4", "5"} {
+		q.Push(hyprland.Notification{Message: m})
+	}
+
+	// The oldest notifications are dropped when the queue is full
+	assert.Equal(t, q.Len(), 3)
+	assert.Equal(t, q.Dropped(), 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	start := time.Now()
+
+	go func() { done <- q.Run(ctx) }()
+
+	// The burst is sent at once, the rest is rate-limited
+	for len(s.Requests()) < 3 {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("notifications were not sent")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	assert.GreaterOrEqual(t, time.Since(start), interval)
+
+	var messages []string
+	for _, req := range s.Requests() {
+		messages = append(messages, req.Raw)
+	}
+
+	assert.DeepEqual(t, messages, []string{
+		"notify -1 5000 0 3",
+		"notify -1 5000 0 4",
+		"notify -1 5000 0 5",
+	})
+	assert.Equal(t, q.Len(), 0)
+
+	cancel()
+	assert.Error(t, <-done)
+}
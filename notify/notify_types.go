@@ -0,0 +1,28 @@
+package notify
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+)
+
+// Queue sends notifications to Hyprland, rate-limiting bursts. Up to burst
+// notifications are sent at once, after that one notification is sent per
+// interval. It is safe to be used concurrently.
+type Queue struct {
+	client *hyprland.RequestClient
+
+	interval   time.Duration
+	burst      int
+	maxPending int
+
+	mu      sync.Mutex
+	pending []hyprland.Notification
+	dropped int
+	// Wakes up Queue.Run when a notification is pushed
+	wake chan struct{}
+}
+
+// Option is used to customise a [Queue] during its creation, see [New].
+type Option func(*Queue)
@@ -3,13 +3,16 @@ package hyprland
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net"
 	"strings"
+	"time"
 
+	"github.com/thiagokokada/hyprland-go/dispatcher"
 	"github.com/thiagokokada/hyprland-go/helpers"
 	"github.com/thiagokokada/hyprland-go/internal/assert"
 )
@@ -30,25 +33,56 @@ var (
 // Initiate a new client or panic.
 // This should be the preferred method for user scripts, since it will
 // automatically find the proper socket to connect and use the
-// HYPRLAND_INSTANCE_SIGNATURE for the current user.
+// HYPRLAND_INSTANCE_SIGNATURE for the current user (or the oldest running
+// instance if it is not set, see helpers.GetSocket). Before, an unset
+// HYPRLAND_INSTANCE_SIGNATURE was an error, now it only is if no instance is
+// running.
 // If you need to connect to arbitrary user instances or need a method that
-// will not panic on error, use [NewClient] instead.
-func MustClient() *RequestClient {
+// will not panic on error, use [NewClient] or [NewClientForInstance] instead.
+func MustClient(opts ...ClientOption) *RequestClient {
 	return NewClient(
 		assert.Must1(helpers.GetSocket(helpers.RequestSocket)),
+		opts...,
 	)
 }
 
 // Initiate a new client.
 // Receive as parameters a requestSocket that is generally localised in
 // '$XDG_RUNTIME_DIR/hypr/$HYPRLAND_INSTANCE_SIGNATURE/.socket.sock'.
-func NewClient(socket string) *RequestClient {
-	return &RequestClient{
+// Optionally receives a list of [ClientOption] to customise the client.
+func NewClient(socket string, opts ...ClientOption) *RequestClient {
+	c := &RequestClient{
 		conn: &net.UnixAddr{
 			Net:  "unix",
 			Name: socket,
 		},
 	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// Initiate a new client for a Hyprland instance, e.g.: one returned by
+// [helpers.Instances] or [helpers.FindInstance]. Useful when
+// HYPRLAND_INSTANCE_SIGNATURE is not set or to connect to a specific
+// instance.
+// Optionally receives a list of [ClientOption] to customise the client.
+func NewClientForInstance(instance helpers.Instance, opts ...ClientOption) *RequestClient {
+	return NewClient(instance.Socket(helpers.RequestSocket), opts...)
+}
+
+// WithTimeout sets a default timeout for each request done by the client,
+// including the time to connect, write and read from the socket.
+// If the [context.Context] passed to the request already has an earlier
+// deadline, it takes precedence. A timeout <= 0 means no timeout, the
+// default.
+func WithTimeout(timeout time.Duration) ClientOption {
+	return func(c *RequestClient) {
+		c.timeout = timeout
+	}
 }
 
 // Low-level request method, should be avoided unless there is no alternative.
@@ -58,13 +92,32 @@ func NewClient(socket string) *RequestClient {
 // Keep in mind that there is no validation. In case of an invalid request, the
 // response will generally be something different from "ok".
 func (c *RequestClient) RawRequest(request RawRequest) (response RawResponse, err error) {
+	return c.RawRequestWithContext(context.Background(), request)
+}
+
+// Same as [RequestClient.RawRequest], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline. Cancelling the context
+// will abort the connection, write and read from the socket.
+func (c *RequestClient) RawRequestWithContext(ctx context.Context, request RawRequest) (response RawResponse, err error) {
 	if len(request) == 0 {
 		return nil, ErrEmptyRequest
 	}
 
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
 	// Connect to the request socket
-	conn, err := net.DialUnix("unix", nil, c.conn)
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, c.conn.Net, c.conn.Name)
 	if err != nil {
+		// Hyprland may be restarted with a different version
+		c.invalidateVersion()
+
 		return nil, fmt.Errorf("error while connecting to socket: %w", err)
 	}
 
@@ -74,6 +127,13 @@ func (c *RequestClient) RawRequest(request RawRequest) (response RawResponse, er
 		}
 	}()
 
+	// Unblock any pending write/read once the context is done
+	stop := context.AfterFunc(ctx, func() {
+		// Ignoring error since the connection may already be closed
+		_ = conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
 	if len(request) > bufSize {
 		return nil, fmt.Errorf(
 			"%w (%d>%d): %s",
@@ -89,12 +149,12 @@ func (c *RequestClient) RawRequest(request RawRequest) (response RawResponse, er
 	// Send the request to the socket
 	_, err = writer.Write(request)
 	if err != nil {
-		return nil, fmt.Errorf("error while writing to socket: %w", err)
+		return nil, fmt.Errorf("error while writing to socket: %w", withContextErr(ctx, err))
 	}
 
 	err = writer.Flush()
 	if err != nil {
-		return nil, fmt.Errorf("error while flushing to socket: %w", err)
+		return nil, fmt.Errorf("error while flushing to socket: %w", withContextErr(ctx, err))
 	}
 
 	// Get the response back
@@ -109,7 +169,7 @@ func (c *RequestClient) RawRequest(request RawRequest) (response RawResponse, er
 				break
 			}
 
-			return nil, fmt.Errorf("error while reading from socket: %w", err)
+			return nil, fmt.Errorf("error while reading from socket: %w", withContextErr(ctx, err))
 		}
 
 		rbuf.Write(sbuf[:n])
@@ -123,86 +183,166 @@ func (c *RequestClient) RawRequest(request RawRequest) (response RawResponse, er
 }
 
 // Active window command, similar to 'hyprctl activewindow'.
-// Returns a [Window] object.
+// Returns a [Window] object. Decode failures caused by a Hyprland version
+// without [CapFullscreenState] also return an [UnsupportedError].
 func (c *RequestClient) ActiveWindow() (w Window, err error) {
-	response, err := c.doRequest("activewindow", nil, true)
+	return c.ActiveWindowWithContext(context.Background())
+}
+
+// Same as [RequestClient.ActiveWindow], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) ActiveWindowWithContext(ctx context.Context) (w Window, err error) {
+	response, err := c.doRequest(ctx, "activewindow", nil, true)
 	if err != nil {
 		return w, err
 	}
 
-	return unmarshalResponse(response, &w)
+	w, err = unmarshalResponse(response, &w, c.strict)
+
+	return w, c.explainErr(ctx, CapFullscreenState, err)
 }
 
 // Get option command, similar to 'hyprctl activeworkspace'.
 // Returns a [Workspace] object.
 func (c *RequestClient) ActiveWorkspace() (w Workspace, err error) {
-	response, err := c.doRequest("activeworkspace", nil, true)
+	return c.ActiveWorkspaceWithContext(context.Background())
+}
+
+// Same as [RequestClient.ActiveWorkspace], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) ActiveWorkspaceWithContext(ctx context.Context) (w Workspace, err error) {
+	response, err := c.doRequest(ctx, "activeworkspace", nil, true)
 	if err != nil {
 		return w, err
 	}
 
-	return unmarshalResponse(response, &w)
+	return unmarshalResponse(response, &w, c.strict)
 }
 
 // Animations command, similar to 'hyprctl animations'.
 // Returns a [Animation] object.
 func (c *RequestClient) Animations() (a [][]Animation, err error) {
-	response, err := c.doRequest("animations", nil, true)
+	return c.AnimationsWithContext(context.Background())
+}
+
+// Same as [RequestClient.Animations], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) AnimationsWithContext(ctx context.Context) (a [][]Animation, err error) {
+	response, err := c.doRequest(ctx, "animations", nil, true)
 	if err != nil {
 		return a, err
 	}
 
-	return unmarshalResponse(response, &a)
+	return unmarshalResponse(response, &a, c.strict)
 }
 
 // Binds command, similar to 'hyprctl binds'.
 // Returns a [Bind] object.
 func (c *RequestClient) Binds() (b []Bind, err error) {
-	response, err := c.doRequest("binds", nil, true)
+	return c.BindsWithContext(context.Background())
+}
+
+// Same as [RequestClient.Binds], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) BindsWithContext(ctx context.Context) (b []Bind, err error) {
+	response, err := c.doRequest(ctx, "binds", nil, true)
 	if err != nil {
 		return b, err
 	}
 
-	return unmarshalResponse(response, &b)
+	return unmarshalResponse(response, &b, c.strict)
 }
 
 // Clients command, similar to 'hyprctl clients'.
-// Returns a [Client] object.
+// Returns a [Client] object. Decode failures caused by a Hyprland version
+// without [CapFullscreenState] also return an [UnsupportedError].
 func (c *RequestClient) Clients() (cl []Client, err error) {
-	response, err := c.doRequest("clients", nil, true)
+	return c.ClientsWithContext(context.Background())
+}
+
+// Same as [RequestClient.Clients], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) ClientsWithContext(ctx context.Context) (cl []Client, err error) {
+	response, err := c.doRequest(ctx, "clients", nil, true)
 	if err != nil {
 		return cl, err
 	}
 
-	return unmarshalResponse(response, &cl)
+	cl, err = unmarshalResponse(response, &cl, c.strict)
+
+	return cl, c.explainErr(ctx, CapFullscreenState, err)
 }
 
 // ConfigErrors command, similar to `hyprctl configerrors`.
 // Returns a [ConfigError] object.
 func (c *RequestClient) ConfigErrors() (ce []ConfigError, err error) {
-	response, err := c.doRequest("configerrors", nil, true)
+	return c.ConfigErrorsWithContext(context.Background())
+}
+
+// Same as [RequestClient.ConfigErrors], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) ConfigErrorsWithContext(ctx context.Context) (ce []ConfigError, err error) {
+	response, err := c.doRequest(ctx, "configerrors", nil, true)
 	if err != nil {
 		return ce, err
 	}
 
-	return unmarshalResponse(response, &ce)
+	return unmarshalResponse(response, &ce, c.strict)
 }
 
 // Cursor position command, similar to 'hyprctl cursorpos'.
 // Returns a [CursorPos] object.
 func (c *RequestClient) CursorPos() (cu CursorPos, err error) {
-	response, err := c.doRequest("cursorpos", nil, true)
+	return c.CursorPosWithContext(context.Background())
+}
+
+// Same as [RequestClient.CursorPos], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) CursorPosWithContext(ctx context.Context) (cu CursorPos, err error) {
+	response, err := c.doRequest(ctx, "cursorpos", nil, true)
 	if err != nil {
 		return cu, err
 	}
 
-	return unmarshalResponse(response, &cu)
+	return unmarshalResponse(response, &cu, c.strict)
+}
+
+// Custom event command, similar to 'hyprctl dispatch event'.
+// Emits a custom event with arbitrary data to the event socket, that can be
+// received by event clients as event.Custom. The data should not contain new
+// lines, otherwise it will be split in multiple events.
+// Returns a [Response], that may be useful for further validations, or an
+// [UnsupportedError] without sending the request if Hyprland does not support
+// [CapCustomEvent].
+func (c *RequestClient) CustomEvent(data string) (r Response, err error) {
+	return c.CustomEventWithContext(context.Background(), data)
+}
+
+// Same as [RequestClient.CustomEvent], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) CustomEventWithContext(ctx context.Context, data string) (r Response, err error) {
+	if err := c.require(ctx, CapCustomEvent); err != nil {
+		return r, err
+	}
+
+	response, err := c.DispatchCommandsWithContext(ctx, dispatcher.Event(data))
+	if err != nil {
+		return r, err
+	}
+
+	return response[0], nil // should return only one response
 }
 
 // Decorations command, similar to `hyprctl decorations`.
 // Returns a [Decoration] object.
 func (c *RequestClient) Decorations(regex string) (d []Decoration, err error) {
-	response, err := c.doRequest("decorations", []string{regex}, true)
+	return c.DecorationsWithContext(context.Background(), regex)
+}
+
+// Same as [RequestClient.Decorations], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) DecorationsWithContext(ctx context.Context, regex string) (d []Decoration, err error) {
+	response, err := c.doRequest(ctx, "decorations", []string{regex}, true)
 	if err != nil {
 		return d, err
 	}
@@ -212,18 +352,24 @@ func (c *RequestClient) Decorations(regex string) (d []Decoration, err error) {
 		return nil, nil
 	}
 
-	return unmarshalResponse(response, &d)
+	return unmarshalResponse(response, &d, c.strict)
 }
 
 // Devices command, similar to `hyprctl devices`.
 // Returns a [Devices] object.
 func (c *RequestClient) Devices() (d Devices, err error) {
-	response, err := c.doRequest("devices", nil, true)
+	return c.DevicesWithContext(context.Background())
+}
+
+// Same as [RequestClient.Devices], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) DevicesWithContext(ctx context.Context) (d Devices, err error) {
+	response, err := c.doRequest(ctx, "devices", nil, true)
 	if err != nil {
 		return d, err
 	}
 
-	return unmarshalResponse(response, &d)
+	return unmarshalResponse(response, &d, c.strict)
 }
 
 // Dispatch commands, similar to 'hyprctl dispatch'.
@@ -232,7 +378,13 @@ func (c *RequestClient) Devices() (d Devices, err error) {
 // Returns a [Response] list for each parameter, that may be useful for further
 // validations.
 func (c *RequestClient) Dispatch(params ...string) (r []Response, err error) {
-	raw, err := c.doRequest("dispatch", params, false)
+	return c.DispatchWithContext(context.Background(), params...)
+}
+
+// Same as [RequestClient.Dispatch], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) DispatchWithContext(ctx context.Context, params ...string) (r []Response, err error) {
+	raw, err := c.doRequest(ctx, "dispatch", params, false)
 	if err != nil {
 		return r, err
 	}
@@ -240,15 +392,38 @@ func (c *RequestClient) Dispatch(params ...string) (r []Response, err error) {
 	return parseAndValidateResponse(params, raw)
 }
 
+// Dispatch typed commands, similar to [RequestClient.Dispatch] but using the
+// dispatchers from the [dispatcher] package, e.g.:
+// 'c.DispatchCommands(dispatcher.Exec("kitty"), dispatcher.MoveFocus(dispatcher.Left))'.
+// Accept multiple commands at the same time, in this case it will use batch
+// mode.
+// Returns a [Response] list for each parameter, that may be useful for further
+// validations.
+func (c *RequestClient) DispatchCommands(ds ...dispatcher.Dispatcher) (r []Response, err error) {
+	return c.DispatchCommandsWithContext(context.Background(), ds...)
+}
+
+// Same as [RequestClient.DispatchCommands], but accepts a [context.Context]
+// that can be used to cancel the request or set a deadline.
+func (c *RequestClient) DispatchCommandsWithContext(ctx context.Context, ds ...dispatcher.Dispatcher) (r []Response, err error) {
+	return c.DispatchWithContext(ctx, dispatcher.Strings(ds...)...)
+}
+
 // Get option command, similar to 'hyprctl getoption'.
 // Returns an [Option] object.
 func (c *RequestClient) GetOption(name string) (o Option, err error) {
-	response, err := c.doRequest("getoption", []string{name}, true)
+	return c.GetOptionWithContext(context.Background(), name)
+}
+
+// Same as [RequestClient.GetOption], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) GetOptionWithContext(ctx context.Context, name string) (o Option, err error) {
+	response, err := c.doRequest(ctx, "getoption", []string{name}, true)
 	if err != nil {
 		return o, err
 	}
 
-	return unmarshalResponse(response, &o)
+	return unmarshalResponse(response, &o, c.strict)
 }
 
 // Keyword command, similar to 'hyprctl keyword'.
@@ -257,7 +432,13 @@ func (c *RequestClient) GetOption(name string) (o Option, err error) {
 // Returns a [Response] list for each parameter, that may be useful for further
 // validations.
 func (c *RequestClient) Keyword(params ...string) (r []Response, err error) {
-	raw, err := c.doRequest("keyword", params, false)
+	return c.KeywordWithContext(context.Background(), params...)
+}
+
+// Same as [RequestClient.Keyword], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) KeywordWithContext(ctx context.Context, params ...string) (r []Response, err error) {
+	raw, err := c.doRequest(ctx, "keyword", params, false)
 	if err != nil {
 		return r, err
 	}
@@ -270,7 +451,13 @@ func (c *RequestClient) Keyword(params ...string) (r []Response, err error) {
 // user to click in the window.
 // Returns a [Response], that may be useful for further validations.
 func (c *RequestClient) Kill() (r Response, err error) {
-	raw, err := c.doRequest("kill", nil, true)
+	return c.KillWithContext(context.Background())
+}
+
+// Same as [RequestClient.Kill], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) KillWithContext(ctx context.Context) (r Response, err error) {
+	raw, err := c.doRequest(ctx, "kill", nil, true)
 	if err != nil {
 		return r, err
 	}
@@ -283,29 +470,47 @@ func (c *RequestClient) Kill() (r Response, err error) {
 // Layer command, similar to 'hyprctl layers'.
 // Returns a [Layer] object.
 func (c *RequestClient) Layers() (l Layers, err error) {
-	response, err := c.doRequest("layers", nil, true)
+	return c.LayersWithContext(context.Background())
+}
+
+// Same as [RequestClient.Layers], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) LayersWithContext(ctx context.Context) (l Layers, err error) {
+	response, err := c.doRequest(ctx, "layers", nil, true)
 	if err != nil {
 		return l, err
 	}
 
-	return unmarshalResponse(response, &l)
+	return unmarshalResponse(response, &l, c.strict)
 }
 
 // Monitors command, similar to 'hyprctl monitors'.
 // Returns a [Monitor] object.
 func (c *RequestClient) Monitors() (m []Monitor, err error) {
-	response, err := c.doRequest("monitors all", nil, true)
+	return c.MonitorsWithContext(context.Background())
+}
+
+// Same as [RequestClient.Monitors], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) MonitorsWithContext(ctx context.Context) (m []Monitor, err error) {
+	response, err := c.doRequest(ctx, "monitors all", nil, true)
 	if err != nil {
 		return m, err
 	}
 
-	return unmarshalResponse(response, &m)
+	return unmarshalResponse(response, &m, c.strict)
 }
 
 // Reload command, similar to 'hyprctl reload'.
 // Returns a [Response], that may be useful for further validations.
 func (c *RequestClient) Reload() (r Response, err error) {
-	raw, err := c.doRequest("reload", nil, false)
+	return c.ReloadWithContext(context.Background())
+}
+
+// Same as [RequestClient.Reload], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) ReloadWithContext(ctx context.Context) (r Response, err error) {
+	raw, err := c.doRequest(ctx, "reload", nil, false)
 	if err != nil {
 		return r, err
 	}
@@ -315,10 +520,80 @@ func (c *RequestClient) Reload() (r Response, err error) {
 	return response[0], err // should return only one response
 }
 
+// Snapshot command, similar to calling 'hyprctl clients', 'hyprctl
+// workspaces', 'hyprctl monitors all', 'hyprctl activewindow' and 'hyprctl
+// activeworkspace' in batch mode.
+// Since all commands are done in a single request, the results are consistent
+// with each other, e.g.: the active window is included in the clients.
+// Returns a [Snapshot] object. If some of the results can not be decoded, the
+// others are still returned, together with all the errors joined.
+// Decode failures caused by a Hyprland version without [CapFullscreenState]
+// also return an [UnsupportedError].
+func (c *RequestClient) Snapshot() (s Snapshot, err error) {
+	return c.SnapshotWithContext(context.Background())
+}
+
+// Same as [RequestClient.Snapshot], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) SnapshotWithContext(ctx context.Context) (s Snapshot, err error) {
+	response, err := c.doBatchRequest(ctx, []batchCommand{
+		{"clients", "", true},
+		{"workspaces", "", true},
+		{"monitors", "all", true},
+		{"activewindow", "", true},
+		{"activeworkspace", "", true},
+	})
+	if err != nil {
+		return s, err
+	}
+
+	docs, err := splitJSONResponse(response, 5)
+	if err != nil {
+		return s, err
+	}
+
+	// Decode all documents, so in strict mode the unknown fields from all of
+	// them are reported
+	var errs []error
+
+	s.Clients, err = unmarshalResponse(docs[0], &s.Clients, c.strict)
+	if err != nil {
+		errs = append(errs, c.explainErr(ctx, CapFullscreenState, err))
+	}
+
+	s.Workspaces, err = unmarshalResponse(docs[1], &s.Workspaces, c.strict)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	s.Monitors, err = unmarshalResponse(docs[2], &s.Monitors, c.strict)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	s.ActiveWindow, err = unmarshalResponse(docs[3], &s.ActiveWindow, c.strict)
+	if err != nil {
+		errs = append(errs, c.explainErr(ctx, CapFullscreenState, err))
+	}
+
+	s.ActiveWorkspace, err = unmarshalResponse(docs[4], &s.ActiveWorkspace, c.strict)
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return s, errors.Join(errs...)
+}
+
 // Set cursor command, similar to 'hyprctl setcursor'.
 // Returns a [Response], that may be useful for further validations.
 func (c *RequestClient) SetCursor(theme string, size int) (r Response, err error) {
-	raw, err := c.doRequest("setcursor", []string{fmt.Sprintf("%s %d", theme, size)}, false)
+	return c.SetCursorWithContext(context.Background(), theme, size)
+}
+
+// Same as [RequestClient.SetCursor], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) SetCursorWithContext(ctx context.Context, theme string, size int) (r Response, err error) {
+	raw, err := c.doRequest(ctx, "setcursor", []string{fmt.Sprintf("%s %d", theme, size)}, false)
 	if err != nil {
 		return r, err
 	}
@@ -332,7 +607,13 @@ func (c *RequestClient) SetCursor(theme string, size int) (r Response, err error
 // Returns a [Response], that may be useful for further validations.
 // Param cmd can be either 'next', 'prev' or an ID (e.g: 0).
 func (c *RequestClient) SwitchXkbLayout(device string, cmd string) (r Response, err error) {
-	raw, err := c.doRequest("switchxkblayout", []string{fmt.Sprintf("%s %s", device, cmd)}, false)
+	return c.SwitchXkbLayoutWithContext(context.Background(), device, cmd)
+}
+
+// Same as [RequestClient.SwitchXkbLayout], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) SwitchXkbLayoutWithContext(ctx context.Context, device string, cmd string) (r Response, err error) {
+	raw, err := c.doRequest(ctx, "switchxkblayout", []string{fmt.Sprintf("%s %s", device, cmd)}, false)
 	if err != nil {
 		return r, err
 	}
@@ -344,7 +625,13 @@ func (c *RequestClient) SwitchXkbLayout(device string, cmd string) (r Response,
 
 // Splash command, similar to 'hyprctl splash'.
 func (c *RequestClient) Splash() (s string, err error) {
-	response, err := c.doRequest("splash", nil, false)
+	return c.SplashWithContext(context.Background())
+}
+
+// Same as [RequestClient.Splash], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) SplashWithContext(ctx context.Context) (s string, err error) {
+	response, err := c.doRequest(ctx, "splash", nil, false)
 	if err != nil {
 		return s, err
 	}
@@ -355,23 +642,35 @@ func (c *RequestClient) Splash() (s string, err error) {
 // Version command, similar to 'hyprctl version'.
 // Returns a [Version] object.
 func (c *RequestClient) Version() (v Version, err error) {
-	response, err := c.doRequest("version", nil, true)
+	return c.VersionWithContext(context.Background())
+}
+
+// Same as [RequestClient.Version], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) VersionWithContext(ctx context.Context) (v Version, err error) {
+	response, err := c.doRequest(ctx, "version", nil, true)
 	if err != nil {
 		return v, err
 	}
 
-	return unmarshalResponse(response, &v)
+	return unmarshalResponse(response, &v, c.strict)
 }
 
 // Workspaces option command, similar to 'hyprctl workspaces'.
 // Returns a [Workspace] object.
 func (c *RequestClient) Workspaces() (w []Workspace, err error) {
-	response, err := c.doRequest("workspaces", nil, true)
+	return c.WorkspacesWithContext(context.Background())
+}
+
+// Same as [RequestClient.Workspaces], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) WorkspacesWithContext(ctx context.Context) (w []Workspace, err error) {
+	response, err := c.doRequest(ctx, "workspaces", nil, true)
 	if err != nil {
 		return w, err
 	}
 
-	return unmarshalResponse(response, &w)
+	return unmarshalResponse(response, &w, c.strict)
 }
 
 const (
@@ -386,6 +685,21 @@ var (
 	reqSep        = []byte{' ', ';'}
 )
 
+// A single command in a batch request, see prepareBatchRequests.
+type batchCommand struct {
+	command  string
+	param    string
+	jsonResp bool
+}
+
+func (c batchCommand) String() string {
+	if c.param == "" {
+		return c.command
+	}
+
+	return c.command + " " + c.param
+}
+
 func prepareRequest(buf *bytes.Buffer, command string, param string, jsonResp bool) int {
 	if jsonResp {
 		buf.Write(jsonReqHeader)
@@ -406,80 +720,84 @@ func prepareRequests(command string, params []string, jsonResp bool) (requests [
 		panic("empty command")
 	}
 
-	// Buffer that will store the temporary prepared request
-	buf := bytes.NewBuffer(nil)
-	bufErr := func() error {
-		return fmt.Errorf(
-			"%w (%d>=%d): %s",
-			ErrCommandTooLong,
-			buf.Len(),
-			bufSize,
-			buf.String(),
-		)
+	if len(params) > 1 {
+		cmds := make([]batchCommand, 0, len(params))
+		for _, param := range params {
+			cmds = append(cmds, batchCommand{command, param, jsonResp})
+		}
+
+		return prepareBatchRequests(cmds)
 	}
 
-	switch len(params) {
-	case 0:
-		if jsonResp {
-			buf.Write(jsonReqHeader)
-		}
+	// Buffer that will store the temporary prepared request
+	buf := bytes.NewBuffer(nil)
 
-		buf.WriteString(command)
+	if jsonResp {
+		buf.Write(jsonReqHeader)
+	}
 
-		if buf.Len() > bufSize {
-			return nil, bufErr()
-		}
-	case 1:
-		if jsonResp {
-			buf.Write(jsonReqHeader)
-		}
+	buf.WriteString(command)
 
-		buf.WriteString(command)
+	if len(params) == 1 {
 		buf.WriteByte(reqSep[0])
 		buf.WriteString(params[0])
+	}
+
+	if buf.Len() > bufSize {
+		return nil, commandTooLongErr(buf)
+	}
+
+	return []RawRequest{buf.Bytes()}, nil
+}
 
-		if buf.Len() > bufSize {
-			return nil, bufErr()
+// Prepare a batch request for the commands, that may be different from each
+// other. If the commands do not fit in a single request, they will be split
+// in multiple requests, keeping the order of the commands.
+func prepareBatchRequests(cmds []batchCommand) (requests []RawRequest, err error) {
+	// Buffer that will store the temporary prepared request
+	buf := bytes.NewBuffer(nil)
+	// Add [[BATCH]] to the buffer
+	buf.WriteString(batch)
+	// Initialise current length of buffer
+	curLen := buf.Len()
+
+	for _, cmd := range cmds {
+		if cmd.command == "" {
+			// Panic since this is not supposed to happen, i.e.:
+			// only by misuse since this function is internal
+			panic("empty command")
 		}
-	default:
-		// Add [[BATCH]] to the buffer
-		buf.WriteString(batch)
-		// Initialise current length of buffer
-		curLen := buf.Len()
 
-		for _, param := range params {
-			// Get the current command + param length + request
-			// header and separators
-			cmdLen := len(command) + len(param) + len(reqSep)
-			if jsonResp {
-				cmdLen += len(jsonReqHeader)
-			}
+		// Get the current command + param length + request header and
+		// separators
+		cmdLen := len(cmd.command) + len(cmd.param) + len(reqSep)
+		if cmd.jsonResp {
+			cmdLen += len(jsonReqHeader)
+		}
 
-			// If batch + command length is bigger than bufSize,
-			// return an error since it will not fit the socket
-			if len(batch)+cmdLen > bufSize {
-				// Call prepare request for error
-				prepareRequest(buf, command, param, jsonResp)
+		// If batch + command length is bigger than bufSize, return an
+		// error since it will not fit the socket
+		if len(batch)+cmdLen > bufSize {
+			// Call prepare request for error
+			prepareRequest(buf, cmd.command, cmd.param, cmd.jsonResp)
 
-				return nil, bufErr()
-			}
+			return nil, commandTooLongErr(buf)
+		}
 
-			// If the current length of the buffer + command +
-			// param is bigger than bufSize, we will need to split
-			// the request
-			if curLen+cmdLen > bufSize {
-				// Append current buffer contents to the
-				// requests array
-				requests = append(requests, buf.Bytes())
-
-				// Reset the current buffer and add [[BATCH]]
-				buf.Reset()
-				buf.WriteString(batch)
-			}
+		// If the current length of the buffer + command + param is
+		// bigger than bufSize, we will need to split the request
+		if curLen+cmdLen > bufSize {
+			// Append current buffer contents to the requests array
+			requests = append(requests, buf.Bytes())
 
-			// Add the contents of the request to the buffer
-			curLen = prepareRequest(buf, command, param, jsonResp)
+			// Use a new buffer (the previous one is referenced by
+			// requests) and add [[BATCH]]
+			buf = bytes.NewBuffer(nil)
+			buf.WriteString(batch)
 		}
+
+		// Add the contents of the request to the buffer
+		curLen = prepareRequest(buf, cmd.command, cmd.param, cmd.jsonResp)
 	}
 	// Append any remaining buffer content to requests array
 	requests = append(requests, buf.Bytes())
@@ -487,6 +805,16 @@ func prepareRequests(command string, params []string, jsonResp bool) (requests [
 	return requests, nil
 }
 
+func commandTooLongErr(buf *bytes.Buffer) error {
+	return fmt.Errorf(
+		"%w (%d>=%d): %s",
+		ErrCommandTooLong,
+		buf.Len(),
+		bufSize,
+		buf.String(),
+	)
+}
+
 func parseResponse(raw RawResponse) (response []Response, err error) {
 	reader := bufio.NewReader(bytes.NewReader(raw))
 	scanner := bufio.NewScanner(reader)
@@ -529,18 +857,64 @@ func validateResponse(params []string, response []Response) ([]Response, error)
 	}
 
 	// validate that all responses are ok
+	var errs []error
+
 	for i, r := range response {
 		if r != "ok" {
-			return response, fmt.Errorf(
-				"%w: non-ok response from param: %s, response: %s",
-				ErrValidation,
-				params[i],
-				r,
-			)
+			var param string
+			// commands without parameters have no param
+			if i < len(params) {
+				param = params[i]
+			}
+
+			errs = append(errs, &ValidationError{
+				Index:    i,
+				Param:    param,
+				Response: r,
+				Reason:   classifyResponse(r),
+			})
 		}
 	}
 
-	return response, nil
+	return response, errors.Join(errs...)
+}
+
+// Classify a non-ok response from Hyprland. Hyprland does not return error
+// codes, so this is based in the error messages.
+func classifyResponse(r Response) ErrorReason {
+	s := strings.ToLower(string(r))
+
+	switch {
+	case strings.Contains(s, "invalid dispatcher"):
+		return ReasonUnknownDispatcher
+	case strings.Contains(s, "config option") && strings.Contains(s, "does not exist"):
+		return ReasonUnknownOption
+	case strings.Contains(s, "no window") ||
+		strings.Contains(s, "window not found") ||
+		strings.Contains(s, "no such window") ||
+		strings.Contains(s, "no matching window"):
+		return ReasonNoWindowMatched
+	case strings.Contains(s, "cannot open shared object") ||
+		strings.Contains(s, "no such file") ||
+		strings.Contains(s, "invalid elf header"):
+		return ReasonInvalidPluginPath
+	case strings.Contains(s, "plugin") &&
+		(strings.Contains(s, "could not be loaded") ||
+			strings.Contains(s, "failed") ||
+			strings.Contains(s, "crashed") ||
+			strings.Contains(s, "mismatch")):
+		return ReasonPluginInitFailed
+	case strings.Contains(s, "prop not found") ||
+		strings.Contains(s, "unknown prop"):
+		return ReasonUnknownProp
+	case strings.Contains(s, "invalid") ||
+		strings.Contains(s, "bad arg") ||
+		strings.Contains(s, "not enough arg") ||
+		strings.Contains(s, "too many arg"):
+		return ReasonInvalidArgument
+	}
+
+	return ReasonUnknown
 }
 
 func parseAndValidateResponse(params []string, raw RawResponse) ([]Response, error) {
@@ -552,33 +926,97 @@ func parseAndValidateResponse(params []string, raw RawResponse) ([]Response, err
 	return validateResponse(params, response)
 }
 
-func unmarshalResponse[T any](response RawResponse, v *T) (T, error) {
-	if len(response) == 0 {
-		return *v, ErrEmptyResponse
+// Split a response containing multiple concatenated JSON documents, e.g.:
+// the response of a batch request with JSON commands. Returns an error if the
+// number of documents is different from want.
+func splitJSONResponse(response RawResponse, want int) ([]RawResponse, error) {
+	var docs []RawResponse
+
+	dec := json.NewDecoder(bytes.NewReader(response))
+
+	for {
+		var doc json.RawMessage
+
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf(
+				"error while splitting response: %w, response: %s",
+				err,
+				response,
+			)
+		}
+
+		docs = append(docs, RawResponse(doc))
 	}
 
-	err := json.Unmarshal(response, &v)
-	if err != nil {
-		return *v, fmt.Errorf(
-			"error while unmarshal: %w, response: %s",
-			err,
+	if len(docs) != want {
+		return docs, fmt.Errorf(
+			"%w: want responses: %d, got: %d, response: %s",
+			ErrValidation,
+			want,
+			len(docs),
 			response,
 		)
 	}
 
-	return *v, nil
+	return docs, nil
 }
 
-func (c *RequestClient) doRequest(command string, params []string, jsonResp bool) (response RawResponse, err error) {
+// Join the error with the context error, if any, so callers can check for
+// e.g. [context.DeadlineExceeded] with [errors.Is].
+func withContextErr(ctx context.Context, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return errors.Join(err, ctxErr)
+	}
+
+	return err
+}
+
+func (c *RequestClient) doRequest(ctx context.Context, command string, params []string, jsonResp bool) (response RawResponse, err error) {
 	requests, err := prepareRequests(command, params, jsonResp)
 	if err != nil {
 		return nil, fmt.Errorf("error while preparing request: %w", err)
 	}
 
+	return c.sendRequests(ctx, requests)
+}
+
+// Do a request for a command with a single param that returns a single
+// non-JSON response, e.g.: "ok".
+func (c *RequestClient) doSingleRequest(ctx context.Context, command string, param string) (r Response, err error) {
+	params := []string{param}
+
+	raw, err := c.doRequest(ctx, command, params, false)
+	if err != nil {
+		return r, err
+	}
+
+	response, err := parseAndValidateResponse(params, raw)
+	if len(response) == 0 {
+		return r, err
+	}
+
+	return response[0], err // should return only one response
+}
+
+func (c *RequestClient) doBatchRequest(ctx context.Context, cmds []batchCommand) (response RawResponse, err error) {
+	requests, err := prepareBatchRequests(cmds)
+	if err != nil {
+		return nil, fmt.Errorf("error while preparing request: %w", err)
+	}
+
+	return c.sendRequests(ctx, requests)
+}
+
+func (c *RequestClient) sendRequests(ctx context.Context, requests []RawRequest) (response RawResponse, err error) {
 	buf := bytes.NewBuffer(nil)
 
 	for _, req := range requests {
-		resp, err := c.RawRequest(req)
+		resp, err := c.RawRequestWithContext(ctx, req)
 		if err != nil {
 			return nil, fmt.Errorf("error while doing request: %w", err)
 		}
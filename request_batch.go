@@ -0,0 +1,143 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thiagokokada/hyprland-go/dispatcher"
+)
+
+// Initiate a new empty batch, see [Batch].
+func NewBatch() *Batch {
+	return &Batch{}
+}
+
+// Dispatch adds dispatch commands to the batch, similar to
+// [RequestClient.Dispatch].
+func (b *Batch) Dispatch(params ...string) *Batch {
+	return b.add("dispatch", params...)
+}
+
+// DispatchCommands adds typed dispatch commands to the batch, similar to
+// [RequestClient.DispatchCommands].
+func (b *Batch) DispatchCommands(ds ...dispatcher.Dispatcher) *Batch {
+	return b.Dispatch(dispatcher.Strings(ds...)...)
+}
+
+// CustomEvent adds a custom event command to the batch, similar to
+// [RequestClient.CustomEvent].
+func (b *Batch) CustomEvent(data string) *Batch {
+	return b.DispatchCommands(dispatcher.Event(data))
+}
+
+// Keyword adds keyword commands to the batch, similar to
+// [RequestClient.Keyword].
+func (b *Batch) Keyword(params ...string) *Batch {
+	return b.add("keyword", params...)
+}
+
+// Reload adds a reload command to the batch, similar to
+// [RequestClient.Reload].
+func (b *Batch) Reload() *Batch {
+	return b.add("reload", "")
+}
+
+// SetCursor adds a set cursor command to the batch, similar to
+// [RequestClient.SetCursor].
+func (b *Batch) SetCursor(theme string, size int) *Batch {
+	return b.add("setcursor", fmt.Sprintf("%s %d", theme, size))
+}
+
+// SetProp adds set prop commands to the batch, similar to
+// [RequestClient.SetProp]. Invalid windows are not added to the batch, and
+// the error is returned by [RequestClient.DoBatch].
+func (b *Batch) SetProp(window dispatcher.WindowSelector, props ...PropSetting) *Batch {
+	if err := validatePropWindow(window); err != nil {
+		b.err = errors.Join(b.err, err)
+
+		return b
+	}
+
+	return b.add("setprop", setPropParams(window, props)...)
+}
+
+// SwitchXkbLayout adds a switch xkb layout command to the batch, similar to
+// [RequestClient.SwitchXkbLayout].
+func (b *Batch) SwitchXkbLayout(device string, cmd string) *Batch {
+	return b.add("switchxkblayout", fmt.Sprintf("%s %s", device, cmd))
+}
+
+// Raw adds raw commands to the batch, for commands not supported by
+// [Batch], e.g.: "dispatch exec kitty". The commands should return a single
+// line response, i.e.: not use JSON output.
+func (b *Batch) Raw(commands ...string) *Batch {
+	for _, c := range commands {
+		command, param, _ := strings.Cut(strings.TrimSpace(c), " ")
+		b.add(command, param)
+	}
+
+	return b
+}
+
+// Len returns the number of commands in the batch.
+func (b *Batch) Len() int {
+	return len(b.cmds)
+}
+
+// Commands returns the commands in the batch, in the same format as
+// [BatchResponse.Command].
+func (b *Batch) Commands() []string {
+	commands := make([]string, 0, len(b.cmds))
+	for _, c := range b.cmds {
+		commands = append(commands, c.String())
+	}
+
+	return commands
+}
+
+func (b *Batch) add(command string, params ...string) *Batch {
+	for _, param := range params {
+		b.cmds = append(b.cmds, batchCommand{command, param, false})
+	}
+
+	return b
+}
+
+// Run the commands from the [Batch] in batch mode, similar to 'hyprctl
+// --batch'. Requests that are too big are split, similar to
+// [RequestClient.Dispatch].
+// Returns a [BatchResponse] list, one for each command in the same order as
+// they were added, that may be useful for further validations.
+// If there were errors while building the batch, they are returned without
+// sending any command.
+func (c *RequestClient) DoBatch(b *Batch) (r []BatchResponse, err error) {
+	return c.DoBatchWithContext(context.Background(), b)
+}
+
+// Same as [RequestClient.DoBatch], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) DoBatchWithContext(ctx context.Context, b *Batch) (r []BatchResponse, err error) {
+	if b.err != nil {
+		return r, b.err
+	}
+
+	if b.Len() == 0 {
+		return r, ErrEmptyRequest
+	}
+
+	raw, err := c.doBatchRequest(ctx, b.cmds)
+	if err != nil {
+		return r, err
+	}
+
+	commands := b.Commands()
+	response, err := parseAndValidateResponse(commands, raw)
+
+	for i := 0; i < min(len(commands), len(response)); i++ {
+		r = append(r, BatchResponse{Command: commands[i], Response: response[i]})
+	}
+
+	return r, err
+}
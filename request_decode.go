@@ -0,0 +1,360 @@
+package hyprland
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// ErrUnknownFields is returned in strict mode when the response has fields
+// unknown by the structs from this library, generally because they were added
+// in a newer Hyprland version, see [WithStrictDecoding]. Use [errors.As] with
+// [UnknownFieldsError] to get more details.
+var ErrUnknownFields = errors.New("unknown fields")
+
+// UnknownFieldsError is returned in strict mode when the response has fields
+// unknown by the structs from this library. It wraps [ErrUnknownFields].
+type UnknownFieldsError struct {
+	// Unknown field names for each struct, e.g.: {"Client": ["newField"]}.
+	Fields map[string][]string
+}
+
+func (e *UnknownFieldsError) Error() string {
+	structs := make([]string, 0, len(e.Fields))
+	for s := range e.Fields {
+		structs = append(structs, s)
+	}
+
+	slices.Sort(structs)
+
+	var sb strings.Builder
+
+	for i, s := range structs {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+
+		fmt.Fprintf(&sb, "%s: %v", s, e.Fields[s])
+	}
+
+	return fmt.Sprintf("%s: %s", ErrUnknownFields, sb.String())
+}
+
+func (e *UnknownFieldsError) Unwrap() error {
+	return ErrUnknownFields
+}
+
+// WithStrictDecoding makes the client return an [UnknownFieldsError] when a
+// response has fields unknown by the structs from this library, useful to
+// detect drift against newer Hyprland versions, e.g.: in tests. The decoded
+// value is still returned together with the error.
+// Without strict mode, unknown fields are only kept in the Extra field from
+// each struct.
+func WithStrictDecoding() ClientOption {
+	return func(c *RequestClient) {
+		c.strict = true
+	}
+}
+
+func unmarshalResponse[T any](response RawResponse, v *T, strict bool) (T, error) {
+	if len(response) == 0 {
+		return *v, ErrEmptyResponse
+	}
+
+	err := json.Unmarshal(response, &v)
+	if err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) && typeErr.Struct != "" {
+			return *v, fmt.Errorf(
+				"error while unmarshal: field %s.%s has JSON type %s, want %s: %w, response: %s",
+				typeErr.Struct,
+				typeErr.Field,
+				typeErr.Value,
+				typeErr.Type,
+				err,
+				response,
+			)
+		}
+
+		return *v, fmt.Errorf(
+			"error while unmarshal: %w, response: %s",
+			err,
+			response,
+		)
+	}
+
+	if strict {
+		fields := make(map[string][]string)
+		collectUnknownFields(reflect.ValueOf(v), fields)
+
+		if len(fields) > 0 {
+			return *v, &UnknownFieldsError{Fields: fields}
+		}
+	}
+
+	return *v, nil
+}
+
+// Unmarshal data in v, that should be a pointer to an alias of a struct (so
+// it does not call UnmarshalJSON recursively). Returns the fields from data
+// that are not known by the struct, or nil if there are none.
+// The name is used in type errors instead of the alias name.
+func unmarshalExtra(data []byte, v any, name string) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			typeErr.Struct = name
+		}
+
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, f := range jsonFields(reflect.TypeOf(v).Elem()) {
+		delete(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+
+	return fields, nil
+}
+
+var workspaceType = reflect.TypeOf(WorkspaceType{})
+
+// Add the unknown fields from nested structs of type t, that have no Extra
+// field so they are still comparable (e.g.: WorkspaceType), to the extra
+// fields of the parent as "name.field", e.g.: "workspace.newField".
+func unmarshalNestedExtra(data []byte, extra map[string]json.RawMessage, t reflect.Type, names ...string) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	known := jsonFields(t)
+
+	for _, name := range names {
+		var nested map[string]json.RawMessage
+		// Ignore errors, e.g.: null or missing values
+		if json.Unmarshal(fields[name], &nested) != nil {
+			continue
+		}
+
+		for k, v := range nested {
+			if slices.Contains(known, k) {
+				continue
+			}
+
+			if extra == nil {
+				extra = make(map[string]json.RawMessage)
+			}
+
+			extra[name+"."+k] = v
+		}
+	}
+
+	return extra, nil
+}
+
+// Returns the JSON field names from a struct type, including the ones from
+// embedded structs.
+func jsonFields(t reflect.Type) (fields []string) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		tag, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if tag == "-" {
+			continue
+		}
+
+		t := f.Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+
+		if f.Anonymous && tag == "" && t.Kind() == reflect.Struct {
+			fields = append(fields, jsonFields(t)...)
+
+			continue
+		}
+
+		if tag == "" {
+			tag = f.Name
+		}
+
+		fields = append(fields, tag)
+	}
+
+	return fields
+}
+
+var extraType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// Walk v collecting the keys from each non-empty Extra field, grouped by the
+// struct name.
+func collectUnknownFields(v reflect.Value, fields map[string][]string) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			collectUnknownFields(v.Elem(), fields)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			collectUnknownFields(v.Index(i), fields)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			collectUnknownFields(iter.Value(), fields)
+		}
+	case reflect.Struct:
+		t := v.Type()
+
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+
+			if f.Name == "Extra" && f.Type == extraType {
+				for k := range v.Field(i).Interface().(map[string]json.RawMessage) {
+					if !slices.Contains(fields[t.Name()], k) {
+						fields[t.Name()] = append(fields[t.Name()], k)
+					}
+				}
+
+				slices.Sort(fields[t.Name()])
+
+				continue
+			}
+
+			collectUnknownFields(v.Field(i), fields)
+		}
+	}
+}
+
+// The methods below keep the unknown fields in the Extra field from each
+// struct, see unmarshalExtra.
+
+func (a *Animation) UnmarshalJSON(data []byte) (err error) {
+	type alias Animation
+	a.Extra, err = unmarshalExtra(data, (*alias)(a), "Animation")
+
+	return err
+}
+
+func (b *Bind) UnmarshalJSON(data []byte) (err error) {
+	type alias Bind
+	b.Extra, err = unmarshalExtra(data, (*alias)(b), "Bind")
+
+	return err
+}
+
+func (c *Client) UnmarshalJSON(data []byte) (err error) {
+	type alias Client
+	c.Extra, err = unmarshalExtra(data, (*alias)(c), "Client")
+	if err != nil {
+		return err
+	}
+
+	c.Extra, err = unmarshalNestedExtra(data, c.Extra, workspaceType, "workspace")
+
+	return err
+}
+
+func (d *Decoration) UnmarshalJSON(data []byte) (err error) {
+	type alias Decoration
+	d.Extra, err = unmarshalExtra(data, (*alias)(d), "Decoration")
+
+	return err
+}
+
+func (d *Devices) UnmarshalJSON(data []byte) (err error) {
+	type alias Devices
+	d.Extra, err = unmarshalExtra(data, (*alias)(d), "Devices")
+
+	return err
+}
+
+func (k *Keyboard) UnmarshalJSON(data []byte) (err error) {
+	type alias Keyboard
+	k.Extra, err = unmarshalExtra(data, (*alias)(k), "Keyboard")
+
+	return err
+}
+
+func (l *Layer) UnmarshalJSON(data []byte) (err error) {
+	type alias Layer
+	l.Extra, err = unmarshalExtra(data, (*alias)(l), "Layer")
+
+	return err
+}
+
+func (l *LayerField) UnmarshalJSON(data []byte) (err error) {
+	type alias LayerField
+	l.Extra, err = unmarshalExtra(data, (*alias)(l), "LayerField")
+
+	return err
+}
+
+func (m *Monitor) UnmarshalJSON(data []byte) (err error) {
+	type alias Monitor
+	m.Extra, err = unmarshalExtra(data, (*alias)(m), "Monitor")
+	if err != nil {
+		return err
+	}
+
+	m.Extra, err = unmarshalNestedExtra(data, m.Extra, workspaceType, "activeWorkspace", "specialWorkspace")
+
+	return err
+}
+
+func (m *Mouse) UnmarshalJSON(data []byte) (err error) {
+	type alias Mouse
+	m.Extra, err = unmarshalExtra(data, (*alias)(m), "Mouse")
+
+	return err
+}
+
+func (o *Option) UnmarshalJSON(data []byte) (err error) {
+	type alias Option
+	o.Extra, err = unmarshalExtra(data, (*alias)(o), "Option")
+
+	return err
+}
+
+func (p *Plugin) UnmarshalJSON(data []byte) (err error) {
+	type alias Plugin
+	p.Extra, err = unmarshalExtra(data, (*alias)(p), "Plugin")
+
+	return err
+}
+
+func (s *Switch) UnmarshalJSON(data []byte) (err error) {
+	type alias Switch
+	s.Extra, err = unmarshalExtra(data, (*alias)(s), "Switch")
+
+	return err
+}
+
+func (v *Version) UnmarshalJSON(data []byte) (err error) {
+	type alias Version
+	v.Extra, err = unmarshalExtra(data, (*alias)(v), "Version")
+
+	return err
+}
+
+func (w *Workspace) UnmarshalJSON(data []byte) (err error) {
+	type alias Workspace
+	w.Extra, err = unmarshalExtra(data, (*alias)(w), "Workspace")
+
+	return err
+}
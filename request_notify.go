@@ -0,0 +1,111 @@
+package hyprland
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default duration of a [Notification].
+const DefaultNotifyDuration = 5 * time.Second
+
+var notifyIconNames = []string{"none", "warning", "info", "hint", "error", "confused", "ok"}
+
+// Returns the name of the icon, e.g.: "warning".
+func (i NotifyIcon) String() string {
+	if i < 0 || int(i) >= len(notifyIconNames) {
+		return "NotifyIcon(" + strconv.Itoa(int(i)) + ")"
+	}
+
+	return notifyIconNames[i]
+}
+
+// Returns the notification in the format expected by 'hyprctl notify', e.g.:
+// "1 5000 rgba(33ccffee) fontsize:20 Hello world".
+func (n Notification) String() string {
+	duration := n.Duration
+	if duration <= 0 {
+		duration = DefaultNotifyDuration
+	}
+
+	var sb strings.Builder
+
+	// Hyprland uses -1 for no icon, see NotifyIcon
+	fmt.Fprintf(&sb, "%d %d %s ", int(n.Icon)-1, duration.Milliseconds(), colorParam(n.Color))
+
+	if n.FontSize > 0 {
+		sb.WriteString("fontsize:" + strconv.Itoa(n.FontSize) + " ")
+	}
+
+	sb.WriteString(n.Message)
+
+	return sb.String()
+}
+
+// Notify command, similar to 'hyprctl notify'.
+// Shows a notification in the top left corner of the focused monitor.
+// Returns a [Response], that may be useful for further validations.
+func (c *RequestClient) Notify(n Notification) (r Response, err error) {
+	return c.NotifyWithContext(context.Background(), n)
+}
+
+// Same as [RequestClient.Notify], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) NotifyWithContext(ctx context.Context, n Notification) (r Response, err error) {
+	return c.doSingleRequest(ctx, "notify", n.String())
+}
+
+// Dismiss notify command, similar to 'hyprctl dismissnotify'.
+// Dismisses the count oldest notifications, or all of them if count <= 0.
+// Returns a [Response], that may be useful for further validations.
+func (c *RequestClient) DismissNotify(count int) (r Response, err error) {
+	return c.DismissNotifyWithContext(context.Background(), count)
+}
+
+// Same as [RequestClient.DismissNotify], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) DismissNotifyWithContext(ctx context.Context, count int) (r Response, err error) {
+	if count <= 0 {
+		count = -1
+	}
+
+	return c.doSingleRequest(ctx, "dismissnotify", strconv.Itoa(count))
+}
+
+// Set error command, similar to 'hyprctl seterror'.
+// Shows an error bar in the top of the screen, similar to the one shown for
+// config errors, until [RequestClient.DisableError] is called.
+// Returns a [Response], that may be useful for further validations.
+func (c *RequestClient) SetError(color Color, message string) (r Response, err error) {
+	return c.SetErrorWithContext(context.Background(), color, message)
+}
+
+// Same as [RequestClient.SetError], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) SetErrorWithContext(ctx context.Context, color Color, message string) (r Response, err error) {
+	return c.doSingleRequest(ctx, "seterror", colorParam(color)+" "+message)
+}
+
+// Disable error command, similar to 'hyprctl seterror disable'.
+// Hides the error bar shown by [RequestClient.SetError].
+// Returns a [Response], that may be useful for further validations.
+func (c *RequestClient) DisableError() (r Response, err error) {
+	return c.DisableErrorWithContext(context.Background())
+}
+
+// Same as [RequestClient.DisableError], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) DisableErrorWithContext(ctx context.Context) (r Response, err error) {
+	return c.doSingleRequest(ctx, "seterror", "disable")
+}
+
+// Hyprland uses 0 as the default color, that would otherwise be transparent.
+func colorParam(c Color) string {
+	if c == (Color{}) {
+		return "0"
+	}
+
+	return c.String()
+}
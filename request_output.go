@@ -0,0 +1,126 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Returned when a created output could not be found in the monitors, see
+// [RequestClient.CreateOutput].
+var ErrOutputNotFound = errors.New("output not found")
+
+// Returns the rule as a monitor keyword, e.g.:
+// 'monitor HEADLESS-2,1920x1080@60,1920x0,1'.
+func (r MonitorRule) String() string {
+	resolution := r.Resolution
+	if resolution == "" {
+		resolution = "preferred"
+	}
+
+	position := r.Position
+	if position == "" {
+		position = "auto"
+	}
+
+	scale := "auto"
+	if r.Scale > 0 {
+		scale = strconv.FormatFloat(r.Scale, 'f', -1, 64)
+	}
+
+	return fmt.Sprintf("monitor %s,%s,%s,%s", r.Name, resolution, position, scale)
+}
+
+// Returns true if the rule sets anything besides the name.
+func (r MonitorRule) hasSettings() bool {
+	return r.Resolution != "" || r.Position != "" || r.Scale > 0
+}
+
+// Create output command, similar to 'hyprctl output create', e.g.: to add a
+// virtual monitor with [OutputHeadless].
+// The output is named after rule.Name, or by Hyprland if empty (e.g.:
+// "HEADLESS-2"). If the rule sets a resolution, position or scale, it is
+// applied with the monitor keyword right after the output is created.
+// Returns the new output as a [Monitor] object, found in
+// [RequestClient.Monitors].
+func (c *RequestClient) CreateOutput(backend OutputBackend, rule MonitorRule) (m Monitor, err error) {
+	return c.CreateOutputWithContext(context.Background(), backend, rule)
+}
+
+// Same as [RequestClient.CreateOutput], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) CreateOutputWithContext(ctx context.Context, backend OutputBackend, rule MonitorRule) (m Monitor, err error) {
+	// Hyprland does not return the name of the new output, so compare the
+	// monitors from before and after its creation
+	before, err := c.MonitorsWithContext(ctx)
+	if err != nil {
+		return m, err
+	}
+
+	param := strings.TrimSpace("create " + string(backend) + " " + rule.Name)
+
+	if _, err = c.doSingleRequest(ctx, "output", param); err != nil {
+		return m, err
+	}
+
+	m, err = c.findOutput(ctx, rule.Name, before)
+	if err != nil {
+		return m, err
+	}
+
+	if !rule.hasSettings() {
+		return m, nil
+	}
+
+	rule.Name = m.Name
+	if _, err = c.KeywordWithContext(ctx, rule.String()); err != nil {
+		return m, err
+	}
+
+	// Fetch the monitor again to return the updated resolution/position
+	return c.findOutput(ctx, m.Name, nil)
+}
+
+// Remove output command, similar to 'hyprctl output remove'.
+// Returns a [Response], that may be useful for further validations.
+func (c *RequestClient) RemoveOutput(name string) (r Response, err error) {
+	return c.RemoveOutputWithContext(context.Background(), name)
+}
+
+// Same as [RequestClient.RemoveOutput], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) RemoveOutputWithContext(ctx context.Context, name string) (r Response, err error) {
+	if name == "" {
+		return r, ErrEmptyRequest
+	}
+
+	return c.doSingleRequest(ctx, "output", "remove "+name)
+}
+
+// Find the output by name, or if the name is empty, the first monitor that is
+// not in before.
+func (c *RequestClient) findOutput(ctx context.Context, name string, before []Monitor) (m Monitor, err error) {
+	monitors, err := c.MonitorsWithContext(ctx)
+	if err != nil {
+		return m, err
+	}
+
+	known := make(map[string]bool, len(before))
+	for _, b := range before {
+		known[b.Name] = true
+	}
+
+	for _, m := range monitors {
+		if (name != "" && m.Name == name) || (name == "" && !known[m.Name]) {
+			return m, nil
+		}
+	}
+
+	if name == "" {
+		return m, fmt.Errorf("%w: no new monitor after creating the output", ErrOutputNotFound)
+	}
+
+	return m, fmt.Errorf("%w: %s", ErrOutputNotFound, name)
+}
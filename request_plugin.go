@@ -0,0 +1,90 @@
+package hyprland
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Plugins command, similar to 'hyprctl plugin list'.
+// Returns a [Plugin] list, empty if no plugins are loaded.
+func (c *RequestClient) Plugins() (p []Plugin, err error) {
+	return c.PluginsWithContext(context.Background())
+}
+
+// Same as [RequestClient.Plugins], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) PluginsWithContext(ctx context.Context) (p []Plugin, err error) {
+	response, err := c.doRequest(ctx, "plugin", []string{"list"}, true)
+	if err != nil {
+		return p, err
+	}
+
+	// Some versions return a message instead of an empty list
+	trimmed := bytes.TrimSpace(response)
+	if !bytes.HasPrefix(trimmed, []byte("[")) &&
+		bytes.Contains(bytes.ToLower(trimmed), []byte("no plugins")) {
+		return []Plugin{}, nil
+	}
+
+	return unmarshalResponse(response, &p, c.strict)
+}
+
+// Load plugin command, similar to 'hyprctl plugin load'.
+// Relative paths are resolved from the current directory, like hyprctl does,
+// since Hyprland may be running in a different one.
+// Returns a [Response], that may be useful for further validations. Failures
+// return a [ValidationError] with either [ReasonInvalidPluginPath] or
+// [ReasonPluginInitFailed] as reason, if they can be classified.
+func (c *RequestClient) LoadPlugin(path string) (r Response, err error) {
+	return c.LoadPluginWithContext(context.Background(), path)
+}
+
+// Same as [RequestClient.LoadPlugin], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) LoadPluginWithContext(ctx context.Context, path string) (r Response, err error) {
+	return c.doPluginRequest(ctx, "load", path)
+}
+
+// Unload plugin command, similar to 'hyprctl plugin unload'.
+// The path should be the same one used to load the plugin, relative paths are
+// resolved like in [RequestClient.LoadPlugin].
+// Returns a [Response], that may be useful for further validations.
+func (c *RequestClient) UnloadPlugin(path string) (r Response, err error) {
+	return c.UnloadPluginWithContext(context.Background(), path)
+}
+
+// Same as [RequestClient.UnloadPlugin], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) UnloadPluginWithContext(ctx context.Context, path string) (r Response, err error) {
+	return c.doPluginRequest(ctx, "unload", path)
+}
+
+func (c *RequestClient) doPluginRequest(ctx context.Context, action string, path string) (r Response, err error) {
+	if path == "" {
+		return r, ErrEmptyRequest
+	}
+
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return r, fmt.Errorf("error while resolving plugin path: %w", err)
+	}
+
+	param := action + " " + path
+
+	raw, err := c.doRequest(ctx, "plugin", []string{param}, false)
+	if err != nil {
+		return r, err
+	}
+
+	// Error messages from plugins may have multiple lines, so the response
+	// is not split by lines like in parseResponse
+	response, err := validateResponse(
+		[]string{param},
+		[]Response{Response(strings.TrimSpace(string(raw)))},
+	)
+
+	return response[0], err // should return only one response
+}
@@ -0,0 +1,324 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/thiagokokada/hyprland-go/dispatcher"
+)
+
+// Returned when a color can not be parsed, see [ParseColor].
+var ErrInvalidColor = errors.New("invalid color")
+
+// ParseColor parses a color in one of the formats accepted by Hyprland, e.g.:
+// 'rgba(33ccffee)', 'rgb(33ccff)', 'rgba(51, 204, 255, 0.93)', 'rgb(51, 204,
+// 255)' or '0xee33ccff' (ARGB). The ARGB format without the '0x' prefix, as
+// returned by 'hyprctl getprop', is also accepted.
+func ParseColor(s string) (c Color, err error) {
+	s = strings.TrimSpace(s)
+
+	if argb, found := strings.CutPrefix(s, "0x"); found {
+		return parseARGB(argb)
+	}
+
+	name, args, found := strings.Cut(s, "(")
+	if !found {
+		return parseARGB(s)
+	}
+
+	args, found = strings.CutSuffix(args, ")")
+	if !found {
+		return c, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+	}
+
+	switch {
+	case name == "rgb" && !strings.Contains(args, ","):
+		v, err := parseHexColor(args, 6)
+		if err != nil {
+			return c, err
+		}
+
+		return Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+	case name == "rgba" && !strings.Contains(args, ","):
+		v, err := parseHexColor(args, 8)
+		if err != nil {
+			return c, err
+		}
+
+		return Color{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}, nil
+	case name == "rgb" || name == "rgba":
+		return parseDecimalColor(name, strings.Split(args, ","))
+	}
+
+	return c, fmt.Errorf("%w: %q", ErrInvalidColor, s)
+}
+
+// Returns the color in the 'rgba(rrggbbaa)' format, e.g.: 'rgba(33ccffee)'.
+func (c Color) String() string {
+	return fmt.Sprintf("rgba(%02x%02x%02x%02x)", c.R, c.G, c.B, c.A)
+}
+
+func parseHexColor(s string, digits int) (uint32, error) {
+	if len(s) != digits {
+		return 0, fmt.Errorf("%w: want %d hex digits, got: %q", ErrInvalidColor, digits, s)
+	}
+
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q: %w", ErrInvalidColor, s, err)
+	}
+
+	return uint32(v), nil
+}
+
+func parseARGB(s string) (c Color, err error) {
+	v, err := parseHexColor(s, 8)
+	if err != nil {
+		return c, err
+	}
+
+	return Color{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: uint8(v >> 24)}, nil
+}
+
+// Parse a color like 'rgb(51, 204, 255)' or 'rgba(51, 204, 255, 0.93)', where
+// the alpha is a float from 0.0 to 1.0.
+func parseDecimalColor(name string, args []string) (c Color, err error) {
+	want := len(name) // 3 for rgb, 4 for rgba
+	if len(args) != want {
+		return c, fmt.Errorf("%w: want %d values, got: %q", ErrInvalidColor, want, args)
+	}
+
+	rgb := make([]uint8, 3)
+	for i := range rgb {
+		v, err := strconv.ParseUint(strings.TrimSpace(args[i]), 10, 8)
+		if err != nil {
+			return c, fmt.Errorf("%w: %w", ErrInvalidColor, err)
+		}
+
+		rgb[i] = uint8(v)
+	}
+
+	c = Color{R: rgb[0], G: rgb[1], B: rgb[2], A: 0xff}
+
+	if want == 4 {
+		a, err := strconv.ParseFloat(strings.TrimSpace(args[3]), 64)
+		if err != nil || a < 0 || a > 1 {
+			return c, fmt.Errorf("%w: alpha should be from 0.0 to 1.0, got: %q", ErrInvalidColor, args[3])
+		}
+
+		c.A = uint8(math.Round(a * 0xff))
+	}
+
+	return c, nil
+}
+
+// Value returns a [PropSetting] for this property, see
+// [RequestClient.SetProp].
+func (p BoolProp) Value(v bool) PropSetting {
+	s := "0"
+	if v {
+		s = "1"
+	}
+
+	return PropSetting{Name: string(p), Value: s}
+}
+
+// Value returns a [PropSetting] for this property, see
+// [RequestClient.SetProp].
+func (p IntProp) Value(v int) PropSetting {
+	return PropSetting{Name: string(p), Value: strconv.Itoa(v)}
+}
+
+// Value returns a [PropSetting] for this property, see
+// [RequestClient.SetProp].
+func (p FloatProp) Value(v float64) PropSetting {
+	return PropSetting{Name: string(p), Value: strconv.FormatFloat(v, 'f', -1, 64)}
+}
+
+// Value returns a [PropSetting] for this property, see
+// [RequestClient.SetProp].
+func (p ColorProp) Value(v Color) PropSetting {
+	return PropSetting{Name: string(p), Value: v.String()}
+}
+
+// Locked returns a copy of the setting with the lock flag set, so the
+// property is not changed by window rules or Hyprland itself.
+func (s PropSetting) Locked() PropSetting {
+	s.Lock = true
+
+	return s
+}
+
+// Returns the setting as expected by 'hyprctl setprop', without the window,
+// e.g.: "alpha 0.8 lock".
+func (s PropSetting) String() string {
+	if s.Lock {
+		return s.Name + " " + s.Value + " lock"
+	}
+
+	return s.Name + " " + s.Value
+}
+
+// Get prop command, similar to 'hyprctl getprop', for properties with a
+// boolean value, e.g.: [PropForceNoBlur].
+// The window is generally selected by its address, e.g.: with
+// [Client.Selector]. Selectors containing spaces are not supported.
+// Returns an [UnsupportedError] without sending the request if Hyprland does
+// not support [CapGetProp].
+func (c *RequestClient) GetPropBool(window dispatcher.WindowSelector, p BoolProp) (v bool, err error) {
+	return c.GetPropBoolWithContext(context.Background(), window, p)
+}
+
+// Same as [RequestClient.GetPropBool], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) GetPropBoolWithContext(ctx context.Context, window dispatcher.WindowSelector, p BoolProp) (v bool, err error) {
+	return getProp(ctx, c, window, string(p), strconv.ParseBool)
+}
+
+// Get prop command, similar to 'hyprctl getprop', for properties with an
+// integer value, e.g.: [PropRounding].
+// The window is generally selected by its address, e.g.: with
+// [Client.Selector]. Selectors containing spaces are not supported.
+// Returns an [UnsupportedError] without sending the request if Hyprland does
+// not support [CapGetProp].
+func (c *RequestClient) GetPropInt(window dispatcher.WindowSelector, p IntProp) (v int, err error) {
+	return c.GetPropIntWithContext(context.Background(), window, p)
+}
+
+// Same as [RequestClient.GetPropInt], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) GetPropIntWithContext(ctx context.Context, window dispatcher.WindowSelector, p IntProp) (v int, err error) {
+	return getProp(ctx, c, window, string(p), strconv.Atoi)
+}
+
+// Get prop command, similar to 'hyprctl getprop', for properties with a
+// float value, e.g.: [PropAlpha].
+// The window is generally selected by its address, e.g.: with
+// [Client.Selector]. Selectors containing spaces are not supported.
+// Returns an [UnsupportedError] without sending the request if Hyprland does
+// not support [CapGetProp].
+func (c *RequestClient) GetPropFloat(window dispatcher.WindowSelector, p FloatProp) (v float64, err error) {
+	return c.GetPropFloatWithContext(context.Background(), window, p)
+}
+
+// Same as [RequestClient.GetPropFloat], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) GetPropFloatWithContext(ctx context.Context, window dispatcher.WindowSelector, p FloatProp) (v float64, err error) {
+	return getProp(ctx, c, window, string(p), func(s string) (float64, error) {
+		return strconv.ParseFloat(s, 64)
+	})
+}
+
+// Get prop command, similar to 'hyprctl getprop', for properties with a
+// color value, e.g.: [PropActiveBorderColor]. For gradients, only the first
+// color is returned.
+// The window is generally selected by its address, e.g.: with
+// [Client.Selector]. Selectors containing spaces are not supported.
+// Returns an [UnsupportedError] without sending the request if Hyprland does
+// not support [CapGetProp].
+func (c *RequestClient) GetPropColor(window dispatcher.WindowSelector, p ColorProp) (v Color, err error) {
+	return c.GetPropColorWithContext(context.Background(), window, p)
+}
+
+// Same as [RequestClient.GetPropColor], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) GetPropColorWithContext(ctx context.Context, window dispatcher.WindowSelector, p ColorProp) (v Color, err error) {
+	return getProp(ctx, c, window, string(p), func(s string) (Color, error) {
+		// gradients are returned as e.g.: 'ee33ccff ee00ff99 45deg'
+		first, _, _ := strings.Cut(s, " ")
+
+		return ParseColor(first)
+	})
+}
+
+// Set prop command, similar to 'hyprctl setprop', e.g.:
+// 'c.SetProp(client.Selector(), PropAlpha.Value(0.8), PropForceNoBlur.Value(true).Locked())'.
+// Accept multiple settings at the same time, in this case it will use batch
+// mode.
+// The window is generally selected by its address, e.g.: with
+// [Client.Selector]. Selectors containing spaces are not supported.
+// Returns a [Response] list for each setting, that may be useful for further
+// validations.
+func (c *RequestClient) SetProp(window dispatcher.WindowSelector, props ...PropSetting) (r []Response, err error) {
+	return c.SetPropWithContext(context.Background(), window, props...)
+}
+
+// Same as [RequestClient.SetProp], but accepts a [context.Context] that
+// can be used to cancel the request or set a deadline.
+func (c *RequestClient) SetPropWithContext(ctx context.Context, window dispatcher.WindowSelector, props ...PropSetting) (r []Response, err error) {
+	if len(props) == 0 {
+		return r, ErrEmptyRequest
+	}
+
+	if err := validatePropWindow(window); err != nil {
+		return r, err
+	}
+
+	params := setPropParams(window, props)
+
+	raw, err := c.doRequest(ctx, "setprop", params, false)
+	if err != nil {
+		return r, err
+	}
+
+	return parseAndValidateResponse(params, raw)
+}
+
+func getProp[T any](ctx context.Context, c *RequestClient, window dispatcher.WindowSelector, name string, parse func(string) (T, error)) (v T, err error) {
+	if err := validatePropWindow(window); err != nil {
+		return v, err
+	}
+
+	if err := c.require(ctx, CapGetProp); err != nil {
+		return v, err
+	}
+
+	param := string(window) + " " + name
+
+	raw, err := c.doRequest(ctx, "getprop", []string{param}, false)
+	if err != nil {
+		return v, err
+	}
+
+	response := Response(strings.TrimSpace(string(raw)))
+
+	v, err = parse(string(response))
+	if err != nil {
+		// Hyprland returns a message instead of the value in case of
+		// errors, e.g.: "prop not found"
+		return v, &ValidationError{
+			Param:    param,
+			Response: response,
+			Reason:   classifyResponse(response),
+		}
+	}
+
+	return v, nil
+}
+
+// Hyprland splits the getprop and setprop arguments by spaces, so selectors
+// with spaces (e.g.: 'title:foo bar') would be parsed wrongly.
+func validatePropWindow(window dispatcher.WindowSelector) error {
+	if window == "" || strings.ContainsAny(string(window), " \t\n") {
+		return fmt.Errorf(
+			"%w: %q, window properties need a selector without spaces, e.g.: an address",
+			dispatcher.ErrInvalidSelector,
+			window,
+		)
+	}
+
+	return nil
+}
+
+func setPropParams(window dispatcher.WindowSelector, props []PropSetting) []string {
+	params := make([]string, 0, len(props))
+	for _, p := range props {
+		params = append(params, string(window)+" "+p.String())
+	}
+
+	return params
+}
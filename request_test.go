@@ -1,14 +1,21 @@
 package hyprland
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/thiagokokada/hyprland-go/dispatcher"
+	"github.com/thiagokokada/hyprland-go/helpers"
+	"github.com/thiagokokada/hyprland-go/hyprlandtest"
 	"github.com/thiagokokada/hyprland-go/internal/assert"
 )
 
@@ -162,6 +169,42 @@ func TestPrepareRequestsError(t *testing.T) {
 	}
 }
 
+func TestPrepareBatchRequests(t *testing.T) {
+	var (
+		cmds []batchCommand
+		want []string
+	)
+
+	for i := 0; i < 1000; i++ {
+		cmds = append(cmds, batchCommand{"dispatch", fmt.Sprintf("workspace %d", i), false})
+		want = append(want, fmt.Sprintf("dispatch workspace %d", i))
+	}
+
+	cmds = append(cmds, batchCommand{"monitors", "all", true})
+	want = append(want, "j/monitors all")
+
+	requests, err := prepareBatchRequests(cmds)
+	assert.NoError(t, err)
+	assert.Greater(t, len(requests), 1)
+
+	// Make sure that the split requests keep all commands in order
+	var got []string
+
+	for _, r := range requests {
+		assert.LessOrEqual(t, len(r), bufSize)
+
+		for _, c := range hyprlandtest.ParseRequest(string(r)).Commands {
+			if c.JSON {
+				got = append(got, c.Flags+"/"+c.String())
+			} else {
+				got = append(got, c.String())
+			}
+		}
+	}
+
+	assert.DeepEqual(t, got, want)
+}
+
 func BenchmarkPrepareRequests(b *testing.B) {
 	params := genParams("param", 10000)
 
@@ -239,6 +282,855 @@ func TestValidateResponse(t *testing.T) {
 	}
 }
 
+func TestSplitJSONResponse(t *testing.T) {
+	tests := []struct {
+		response RawResponse
+		n        int
+		want     []RawResponse
+		wantErr  bool
+	}{
+		{RawResponse(`{"a": 1}`), 1, []RawResponse{RawResponse(`{"a": 1}`)}, false},
+		{RawResponse("[1, 2]\n\n{}\n\n\"x\"\n\n"), 3, []RawResponse{RawResponse("[1, 2]"), RawResponse("{}"), RawResponse(`"x"`)}, false},
+		{RawResponse("[]{}[]"), 3, []RawResponse{RawResponse("[]"), RawResponse("{}"), RawResponse("[]")}, false},
+		// missing response
+		{RawResponse("[]{}"), 3, []RawResponse{RawResponse("[]"), RawResponse("{}")}, true},
+		// non-JSON response
+		{RawResponse("[]{}unknown request"), 3, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.response), func(t *testing.T) {
+			got, err := splitJSONResponse(tt.response, tt.n)
+			assert.DeepEqual(t, got, tt.want)
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	tests := []struct {
+		response Response
+		want     ErrorReason
+	}{
+		{"Invalid dispatcher", ReasonUnknownDispatcher},
+		{"Invalid dispatcher, requested \"foo\" does not exist", ReasonUnknownDispatcher},
+		{"config option <foo:bar> does not exist.", ReasonUnknownOption},
+		{"No window found", ReasonNoWindowMatched},
+		{"Invalid arg", ReasonInvalidArgument},
+		{"prop not found", ReasonUnknownProp},
+		{"Plugin /tmp/foo.so could not be loaded: /tmp/foo.so: invalid ELF header", ReasonInvalidPluginPath},
+		{"Plugin /tmp/foo.so could not be loaded: plugin version mismatch", ReasonPluginInitFailed},
+		{"something else", ReasonUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.response), func(t *testing.T) {
+			params := []string{"exec kitty", "param"}
+			_, err := validateResponse(params, []Response{"ok", tt.response})
+			assert.True(t, errors.Is(err, ErrValidation))
+
+			var verr *ValidationError
+			assert.True(t, errors.As(err, &verr))
+			assert.DeepEqual(t, verr, &ValidationError{
+				Index:    1,
+				Param:    "param",
+				Response: tt.response,
+				Reason:   tt.want,
+			})
+		})
+	}
+
+	// Commands without params should not panic
+	_, err := validateResponse(nil, []Response{"Invalid arg"})
+
+	var verr *ValidationError
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, verr.Param, "")
+	assert.Equal(t, verr.Reason, ReasonInvalidArgument)
+}
+
+func TestParseSemVer(t *testing.T) {
+	tests := []struct {
+		tag     string
+		want    SemVer
+		wantErr bool
+	}{
+		{"v0.47.2", SemVer{0, 47, 2, 0}, false},
+		{"0.47.2", SemVer{0, 47, 2, 0}, false},
+		{"v0.47.2-35-gdeadbeef", SemVer{0, 47, 2, 35}, false},
+		{"v0.48.0-rc1", SemVer{0, 48, 0, 0}, false},
+		{"v0.47", SemVer{}, true},
+		{"unknown", SemVer{}, true},
+		{"", SemVer{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tag, func(t *testing.T) {
+			got, err := ParseSemVer(tt.tag)
+			assert.Equal(t, got, tt.want)
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidVersion))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		s       string
+		want    Color
+		wantErr bool
+	}{
+		{"rgba(33ccffee)", Color{0x33, 0xcc, 0xff, 0xee}, false},
+		{"rgb(33ccff)", Color{0x33, 0xcc, 0xff, 0xff}, false},
+		{"rgba(51, 204, 255, 0.5)", Color{0x33, 0xcc, 0xff, 0x80}, false},
+		{"rgb(51,204,255)", Color{0x33, 0xcc, 0xff, 0xff}, false},
+		{"0xee33ccff", Color{0x33, 0xcc, 0xff, 0xee}, false},
+		{"ee33ccff", Color{0x33, 0xcc, 0xff, 0xee}, false},
+		{"rgb(33ccffee)", Color{}, true},
+		{"rgba(51, 204, 255, 2)", Color{0x33, 0xcc, 0xff, 0xff}, true},
+		{"rgb(256, 0, 0)", Color{}, true},
+		{"hsl(0, 0, 0)", Color{}, true},
+		{"rgba(33ccffee", Color{}, true},
+		{"", Color{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, err := ParseColor(tt.s)
+			assert.Equal(t, got, tt.want)
+
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidColor))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.Equal(t, Color{0x33, 0xcc, 0xff, 0xee}.String(), "rgba(33ccffee)")
+}
+
+func TestVersionSemVer(t *testing.T) {
+	tests := []struct {
+		version Version
+		want    SemVer
+	}{
+		{Version{Tag: "v0.47.2-35-gdeadbeef", Commits: "5700"}, SemVer{0, 47, 2, 35}},
+		// Release builds are not development builds, even if Commits is set
+		{Version{Tag: "v0.47.2", Commits: "5700"}, SemVer{0, 47, 2, 0}},
+		{Version{Tag: "v0.47.2"}, SemVer{0, 47, 2, 0}},
+		{Version{Tag: "v0.48.0-rc1", Commits: "5700"}, SemVer{0, 48, 0, 0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version.Tag, func(t *testing.T) {
+			got, err := tt.version.SemVer()
+			assert.NoError(t, err)
+			assert.Equal(t, got, tt.want)
+		})
+	}
+
+	v := Version{Flags: []string{"debug", "no xwayland"}}
+	assert.True(t, v.HasFlag(FlagDebug))
+	assert.True(t, v.HasFlag(FlagNoXWayland))
+	assert.False(t, v.HasFlag(FlagNoSystemd))
+}
+
+func TestSemVerCompare(t *testing.T) {
+	tests := []struct {
+		a, b SemVer
+		want int
+	}{
+		{SemVer{0, 47, 2, 0}, SemVer{0, 47, 2, 0}, 0},
+		{SemVer{0, 47, 2, 0}, SemVer{0, 47, 10, 0}, -1},
+		{SemVer{0, 48, 0, 0}, SemVer{0, 47, 10, 0}, 1},
+		{SemVer{1, 0, 0, 0}, SemVer{0, 99, 0, 0}, 1},
+		{SemVer{0, 47, 2, 1}, SemVer{0, 47, 2, 0}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s-%s", tt.a, tt.b), func(t *testing.T) {
+			assert.Equal(t, tt.a.Compare(tt.b), tt.want)
+			assert.Equal(t, tt.b.Compare(tt.a), -tt.want)
+			assert.Equal(t, tt.a.AtLeast(tt.b), tt.want >= 0)
+		})
+	}
+
+	assert.Equal(t, SemVer{0, 47, 2, 35}.String(), "0.47.2+35")
+	assert.Equal(t, SemVer{0, 47, 2, 0}.String(), HYPRLAND_VERSION)
+}
+
+// Starts a server that accepts connections but never answers them, simulating
+// a hung Hyprland instance.
+func hangingServer(t *testing.T) string {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), ".socket.sock")
+
+	l, err := net.Listen("unix", socket)
+	assert.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+
+	go func() {
+		var conns []net.Conn
+
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				// Listener was closed, cleanup the connections
+				for _, c := range conns {
+					c.Close()
+				}
+
+				return
+			}
+
+			conns = append(conns, conn)
+		}
+	}()
+
+	return socket
+}
+
+func TestRawRequestWithContext(t *testing.T) {
+	client := NewClient(hangingServer(t))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.RawRequestWithContext(ctx, []byte("splash"))
+	elapsed := time.Since(start)
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
+
+	// Cancelling the context should also abort the request
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+
+	_, err = client.SplashWithContext(ctx)
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestClientWithTimeout(t *testing.T) {
+	client := NewClient(hangingServer(t), WithTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	_, err := client.Splash()
+	elapsed := time.Since(start)
+
+	assert.Error(t, err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.GreaterOrEqual(t, elapsed, 100*time.Millisecond)
+}
+
+func newFakeClient(t *testing.T) (*RequestClient, *hyprlandtest.Server) {
+	t.Helper()
+
+	s := hyprlandtest.NewServer(t)
+
+	return NewClient(s.Socket), s
+}
+
+func TestFakeDispatch(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("dispatch", "ok")
+
+	params := genParams("exec kitty", 1000)
+	r, err := client.Dispatch(params...)
+	assert.NoError(t, err)
+	assert.Equal(t, len(r), len(params))
+
+	// Make sure the commands were split in multiple batch requests
+	requests := s.Requests()
+	assert.Greater(t, len(requests), 1)
+
+	for _, req := range requests {
+		assert.True(t, req.Batch)
+		assert.LessOrEqual(t, len(req.Raw), bufSize)
+	}
+
+	assert.Equal(t, len(s.Commands()), len(params))
+}
+
+func TestFakeDecorations(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleScript("decorations", "none", `[{"decorationName": "Border", "priority": 10000}]`)
+
+	d, err := client.Decorations("kitty")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, d, []Decoration(nil))
+
+	d, err = client.Decorations("kitty")
+	assert.NoError(t, err)
+	assert.DeepEqual(t, d, []Decoration{{DecorationName: "Border", Priority: 10000}})
+	assert.Equal(t, s.Commands()[0].Args, "kitty")
+}
+
+func TestFakeCustomEvent(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("version", `{"tag": "v0.47.2"}`)
+	s.HandleResponse("dispatch", "ok")
+
+	r, err := client.CustomEvent(`{"foo": "bar; baz"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, r, "ok")
+	assert.DeepEqual(t, s.Commands(), []hyprlandtest.Command{
+		{Flags: "j", JSON: true, Name: "version"},
+		{Name: "dispatch", Args: `event {"foo": "bar; baz"}`},
+	})
+
+	// Older versions fail without sending the request
+	client, s = newFakeClient(t)
+	s.HandleResponse("version", `{"tag": "v0.38.0"}`)
+
+	_, err = client.CustomEvent("foo")
+	assert.True(t, errors.Is(err, ErrUnsupported))
+
+	// The version is fetched again before failing, in case Hyprland was
+	// upgraded
+	assert.DeepEqual(t, s.Commands(), []hyprlandtest.Command{
+		{Flags: "j", JSON: true, Name: "version"},
+		{Flags: "j", JSON: true, Name: "version"},
+	})
+
+	// So upgrades are detected without creating a new client
+	s.HandleResponse("version", `{"tag": "v0.47.2"}`)
+	s.HandleResponse("dispatch", "ok")
+
+	_, err = client.CustomEvent("foo")
+	assert.NoError(t, err)
+}
+
+func TestFakeSnapshot(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("clients", `[{"address": "0x1"}, {"address": "0x2"}]`)
+	s.HandleResponse("workspaces", `[{"id": 1, "name": "1", "lastwindow": "0x2"}]`)
+	s.HandleResponse("monitors", `[{"id": 0, "name": "DP-1"}]`)
+	s.HandleResponse("activewindow", `{"address": "0x2"}`)
+	s.HandleResponse("activeworkspace", `{"id": 1, "name": "1", "lastwindow": "0x2"}`)
+
+	snap, err := client.Snapshot()
+	assert.NoError(t, err)
+	assert.Equal(t, len(snap.Clients), 2)
+	assert.Equal(t, snap.Workspaces[0].LastWindow, "0x2")
+	assert.Equal(t, snap.Monitors[0].Name, "DP-1")
+	assert.Equal(t, snap.ActiveWindow.Address, "0x2")
+	assert.Equal(t, snap.ActiveWorkspace.Id, 1)
+
+	// Everything should be fetched in a single request
+	requests := s.Requests()
+	assert.Equal(t, len(requests), 1)
+	assert.Equal(t, requests[0].Raw, "[[BATCH]]j/clients ;j/workspaces ;j/monitors all;j/activewindow ;j/activeworkspace ;")
+
+	// Strict mode reports unknown fields from all results, returning the
+	// decoded ones
+	s.HandleResponse("workspaces", `[{"id": 1, "name": "1", "newField": true}]`)
+	s.HandleResponse("monitors", `[{"id": 0, "name": "DP-1", "otherField": 1}]`)
+
+	snap, err = NewClient(s.Socket, WithStrictDecoding()).Snapshot()
+	assert.True(t, errors.Is(err, ErrUnknownFields))
+	assert.True(t, strings.Contains(err.Error(), "newField"))
+	assert.True(t, strings.Contains(err.Error(), "otherField"))
+	assert.Equal(t, snap.Workspaces[0].Id, 1)
+	assert.Equal(t, snap.Monitors[0].Name, "DP-1")
+	assert.Equal(t, snap.ActiveWindow.Address, "0x2")
+	assert.Equal(t, snap.ActiveWorkspace.Id, 1)
+
+	// Missing responses are returned as errors
+	s.HandleResponse("activeworkspace", "")
+
+	_, err = client.Snapshot()
+	assert.True(t, errors.Is(err, ErrValidation))
+}
+
+func TestFakeDoBatch(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("dispatch", "ok")
+	s.HandleResponse("keyword", "ok")
+	s.HandleResponse("setcursor", "ok")
+	s.HandleScript("switchxkblayout", "ok", "device not found")
+
+	b := NewBatch().
+		DispatchCommands(dispatcher.Exec("kitty"), dispatcher.MoveFocus(dispatcher.Left)).
+		Keyword("general:border_size 1").
+		SetCursor("Adwaita", 32).
+		SwitchXkbLayout("keyboard", "next").
+		Raw("dispatch workspace 1")
+
+	r, err := client.DoBatch(b)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, r, []BatchResponse{
+		{Command: "dispatch exec kitty", Response: "ok"},
+		{Command: "dispatch movefocus l", Response: "ok"},
+		{Command: "keyword general:border_size 1", Response: "ok"},
+		{Command: "setcursor Adwaita 32", Response: "ok"},
+		{Command: "switchxkblayout keyboard next", Response: "ok"},
+		{Command: "dispatch workspace 1", Response: "ok"},
+	})
+	assert.Equal(t, len(s.Requests()), 1)
+
+	// Responses are matched back to the command that produced them
+	r, err = client.DoBatch(NewBatch().SwitchXkbLayout("foo", "next").Reload())
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.DeepEqual(t, r, []BatchResponse{
+		{Command: "switchxkblayout foo next", Response: "device not found"},
+		{Command: "reload", Response: hyprlandtest.UnknownRequest},
+	})
+
+	_, err = client.DoBatch(NewBatch())
+	assert.True(t, errors.Is(err, ErrEmptyRequest))
+
+	// Errors while building the batch are returned without sending it
+	requests := len(s.Requests())
+	_, err = client.DoBatch(NewBatch().
+		Reload().
+		SetProp("title:foo bar", PropAlpha.Value(0.8)))
+	assert.True(t, errors.Is(err, dispatcher.ErrInvalidSelector))
+	assert.Equal(t, len(s.Requests()), requests)
+
+	// Big batches are split in multiple requests
+	b = NewBatch()
+	for i := 0; i < 500; i++ {
+		b.Dispatch(fmt.Sprintf("workspace %d", i)).Keyword(fmt.Sprintf("general:border_size %d", i))
+	}
+
+	r, err = client.DoBatch(b)
+	assert.NoError(t, err)
+	assert.Equal(t, len(r), b.Len())
+	assert.Equal(t, r[999].Command, "keyword general:border_size 499")
+	assert.Greater(t, len(s.Requests()), 3)
+}
+
+func TestFakeHyprlandVersion(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("version", `{"tag": "v0.47.2-35-gdeadbeef", "commits": "5700", "flags": ["debug"]}`)
+
+	for i := 0; i < 3; i++ {
+		v, err := client.HyprlandVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, v, SemVer{0, 47, 2, 35})
+	}
+
+	ok, err := client.HasFlag(FlagDebug)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.HasFlag(FlagNoXWayland)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// The version should be cached
+	assert.Equal(t, len(s.Requests()), 1)
+
+	ok, err = client.Supports(CapFullscreenState)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.Supports(Capability{"future", SemVer{1, 0, 0, 0}})
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// Development builds without tags are assumed to support everything
+	client, s = newFakeClient(t)
+	s.HandleResponse("version", `{"tag": "unknown"}`)
+
+	ok, err = client.Supports(Capability{"future", SemVer{1, 0, 0, 0}})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestFakeUnsupported(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("version", `{"tag": "v0.40.0"}`)
+	s.HandleResponse("clients", `[{"address": "0x1", "fullscreen": true}]`)
+
+	_, err := client.Clients()
+	assert.True(t, errors.Is(err, ErrUnsupported))
+
+	var uerr *UnsupportedError
+	assert.True(t, errors.As(err, &uerr))
+	assert.Equal(t, uerr.Capability, CapFullscreenState)
+	assert.Equal(t, uerr.Version, SemVer{0, 40, 0, 0})
+
+	s.HandleResponse("workspaces", `[]`)
+	s.HandleResponse("monitors", `[]`)
+	s.HandleResponse("activewindow", `{}`)
+	s.HandleResponse("activeworkspace", `{}`)
+
+	_, err = client.Snapshot()
+	assert.True(t, errors.Is(err, ErrUnsupported))
+
+	// Commands that do not exist in older versions are not sent
+	_, err = client.GetPropBool(Client{Address: "0x1"}.Selector(), PropForceNoBlur)
+	assert.True(t, errors.As(err, &uerr))
+	assert.Equal(t, uerr.Capability, CapGetProp)
+
+	for _, cmd := range s.Commands() {
+		assert.True(t, cmd.Name != "getprop")
+	}
+
+	ok, err := client.Supports(CapWorkspaceV2Events)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	// The version is only checked after decode failures
+	client, s = newFakeClient(t)
+	s.HandleResponse("clients", `[{"address": "0x1"}]`)
+
+	_, err = client.Clients()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, s.Commands(), []hyprlandtest.Command{
+		{Flags: "j", JSON: true, Name: "clients"},
+	})
+
+	// Errors are kept as is for supported versions
+	s.HandleResponse("version", `{"tag": "v0.47.0"}`)
+	s.HandleResponse("clients", `[{"address": "0x1", "fullscreen": true}]`)
+
+	client = NewClient(s.Socket)
+	_, err = client.Clients()
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUnsupported))
+}
+
+func TestFakeStrictDecoding(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("clients", `[{"address": "0x1", "workspace": {"id": 1, "name": "1", "new": 1}, "newField": true}]`)
+
+	// Unknown fields are kept in Extra
+	clients, err := client.Clients()
+	assert.NoError(t, err)
+	assert.Equal(t, clients[0].Address, "0x1")
+	assert.Equal(t, string(clients[0].Extra["newField"]), "true")
+
+	// Strict mode reports them, returning the decoded value
+	client = NewClient(s.Socket, WithStrictDecoding())
+	clients, err = client.Clients()
+	assert.True(t, errors.Is(err, ErrUnknownFields))
+	assert.Equal(t, clients[0].Address, "0x1")
+
+	var uerr *UnknownFieldsError
+	assert.True(t, errors.As(err, &uerr))
+	assert.DeepEqual(t, uerr.Fields, map[string][]string{
+		"Client": {"newField", "workspace.new"},
+	})
+
+	// Small value types are still comparable, so their unknown fields are
+	// kept by the parent
+	assert.Equal(t, clients[0].Workspace, WorkspaceType{Id: 1, Name: "1"})
+	assert.Equal(t, string(clients[0].Extra["workspace.new"]), "1")
+
+	// Nested structs are also checked
+	s.HandleResponse("devices", `{
+		"mice": [{"address": "0x1", "name": "mouse", "scrollFactor": 1}],
+		"keyboards": [{"address": "0x2", "name": "keyboard", "numLock": true}],
+		"switches": [{"address": "0x3", "name": "lid", "state": 1}]
+	}`)
+
+	devices, err := client.Devices()
+	assert.True(t, errors.As(err, &uerr))
+	assert.DeepEqual(t, uerr.Fields, map[string][]string{
+		"Keyboard": {"numLock"},
+		"Mouse":    {"scrollFactor"},
+		"Switch":   {"state"},
+	})
+	assert.Equal(t, devices.Keyboards[0].Name, "keyboard")
+	assert.Equal(t, string(devices.Mice[0].Extra["scrollFactor"]), "1")
+
+	// Fields from the embedded WorkspaceType are known by Workspace
+	s.HandleResponse("workspaces", `[{"id": 1, "name": "1", "monitor": "DP-1", "newField": 1}]`)
+
+	workspaces, err := client.Workspaces()
+	assert.True(t, errors.As(err, &uerr))
+	assert.DeepEqual(t, uerr.Fields, map[string][]string{"Workspace": {"newField"}})
+	assert.Equal(t, workspaces[0].Id, 1)
+	assert.Equal(t, workspaces[0].Monitor, "DP-1")
+
+	// Type errors include the struct and field names
+	s.HandleResponse("clients", `[{"address": "0x1", "pid": "1"}]`)
+
+	_, err = client.Clients()
+	assert.Error(t, err)
+	assert.True(t, strings.Contains(err.Error(), "field Client.pid"))
+}
+
+func TestFakeProps(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("version", `{"tag": "v0.47.2"}`)
+	s.Handle("getprop", func(cmd hyprlandtest.Command) string {
+		switch cmd.Args {
+		case "address:0x1 forcenoblur":
+			return "true"
+		case "address:0x1 rounding":
+			return "10"
+		case "address:0x1 alpha":
+			return "0.8"
+		case "address:0x1 activebordercolor":
+			return "ee33ccff ee00ff99 45deg"
+		}
+
+		return "prop not found"
+	})
+	s.HandleResponse("setprop", "ok")
+
+	window := Client{Address: "0x1"}.Selector()
+
+	b, err := client.GetPropBool(window, PropForceNoBlur)
+	assert.NoError(t, err)
+	assert.True(t, b)
+
+	i, err := client.GetPropInt(window, PropRounding)
+	assert.NoError(t, err)
+	assert.Equal(t, i, 10)
+
+	f, err := client.GetPropFloat(window, PropAlpha)
+	assert.NoError(t, err)
+	assert.Equal(t, f, 0.8)
+
+	c, err := client.GetPropColor(window, PropActiveBorderColor)
+	assert.NoError(t, err)
+	assert.Equal(t, c, Color{0x33, 0xcc, 0xff, 0xee})
+
+	_, err = client.GetPropBool(window, BoolProp("foo"))
+
+	var verr *ValidationError
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, verr.Reason, ReasonUnknownProp)
+
+	_, err = client.GetPropFloat(dispatcher.WindowSelector("title:foo bar"), PropAlpha)
+	assert.True(t, errors.Is(err, dispatcher.ErrInvalidSelector))
+
+	r, err := client.SetProp(
+		window,
+		PropAlpha.Value(0.5),
+		PropNoFocus.Value(false),
+		PropInactiveBorderColor.Value(Color{0xff, 0, 0, 0xff}).Locked(),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, len(r), 3)
+
+	requests := s.Requests()
+	assert.Equal(
+		t,
+		requests[len(requests)-1].Raw,
+		"[[BATCH]]setprop address:0x1 alpha 0.5;setprop address:0x1 nofocus 0;setprop address:0x1 inactivebordercolor rgba(ff0000ff) lock;",
+	)
+
+	_, err = client.SetProp(window)
+	assert.True(t, errors.Is(err, ErrEmptyRequest))
+}
+
+func TestFakePlugins(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleScript(
+		"plugin",
+		`[{"name": "hyprbars", "author": "Vaxry", "handle": "7f12ab", "version": "1.0", "description": "Title bars"}]`,
+		"no plugins loaded",
+	)
+
+	p, err := client.Plugins()
+	assert.NoError(t, err)
+	assert.DeepEqual(t, p, []Plugin{
+		{Name: "hyprbars", Author: "Vaxry", Handle: "7f12ab", Version: "1.0", Description: "Title bars"},
+	})
+
+	p, err = client.Plugins()
+	assert.NoError(t, err)
+	assert.Equal(t, len(p), 0)
+
+	s.HandleScript(
+		"plugin",
+		"ok",
+		"error in loading plugin, last error: Plugin /tmp/foo.so could not be loaded: /tmp/foo.so: cannot open shared object file: No such file or directory",
+		"error in loading plugin, last error: Plugin /tmp/bar.so could not be loaded: plugin crashed in init",
+	)
+
+	r, err := client.LoadPlugin("hyprbars.so")
+	assert.NoError(t, err)
+	assert.Equal(t, r, "ok")
+
+	// Relative paths are resolved by the client
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+
+	requests := s.Requests()
+	assert.Equal(t, requests[len(requests)-1].Raw, "plugin load "+filepath.Join(wd, "hyprbars.so"))
+
+	var verr *ValidationError
+
+	_, err = client.LoadPlugin("/tmp/foo.so")
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, verr.Reason, ReasonInvalidPluginPath)
+
+	_, err = client.LoadPlugin("/tmp/bar.so")
+	assert.True(t, errors.As(err, &verr))
+	assert.Equal(t, verr.Reason, ReasonPluginInitFailed)
+
+	_, err = client.UnloadPlugin("")
+	assert.True(t, errors.Is(err, ErrEmptyRequest))
+}
+
+func TestMonitorRule(t *testing.T) {
+	assert.Equal(t, MonitorRule{Name: "DP-1"}.String(), "monitor DP-1,preferred,auto,auto")
+	assert.Equal(
+		t,
+		MonitorRule{Name: "HEADLESS-2", Resolution: "1920x1080@60", Position: "1920x0", Scale: 1.5}.String(),
+		"monitor HEADLESS-2,1920x1080@60,1920x0,1.5",
+	)
+}
+
+func TestFakeOutputs(t *testing.T) {
+	client, s := newFakeClient(t)
+
+	var (
+		mu       sync.Mutex
+		monitors = []string{`{"id": 0, "name": "DP-1", "width": 2560}`}
+	)
+
+	s.Handle("monitors", func(hyprlandtest.Command) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return "[" + strings.Join(monitors, ",") + "]"
+	})
+	s.Handle("output", func(cmd hyprlandtest.Command) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch cmd.Args {
+		case "create headless":
+			monitors = append(monitors, `{"id": 1, "name": "HEADLESS-2", "width": 1920}`)
+		case "create headless tablet":
+			monitors = append(monitors, `{"id": 2, "name": "tablet", "width": 1920}`)
+		case "create wayland", "remove HEADLESS-2":
+			// monitors are not changed
+		default:
+			return "unknown output"
+		}
+
+		return "ok"
+	})
+	s.Handle("keyword", func(cmd hyprlandtest.Command) string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if cmd.Args == "monitor tablet,1280x800,2560x0,auto" {
+			monitors[len(monitors)-1] = `{"id": 2, "name": "tablet", "width": 1280, "x": 2560}`
+		}
+
+		return "ok"
+	})
+
+	m, err := client.CreateOutput(OutputHeadless, MonitorRule{})
+	assert.NoError(t, err)
+	assert.Equal(t, m.Name, "HEADLESS-2")
+	assert.Equal(t, m.Width, 1920)
+
+	// Rules are applied after the output is created
+	m, err = client.CreateOutput(OutputHeadless, MonitorRule{Name: "tablet", Resolution: "1280x800", Position: "2560x0"})
+	assert.NoError(t, err)
+	assert.Equal(t, m.Name, "tablet")
+	assert.Equal(t, m.Width, 1280)
+	assert.Equal(t, m.X, 2560)
+
+	_, err = client.CreateOutput(OutputWayland, MonitorRule{})
+	assert.True(t, errors.Is(err, ErrOutputNotFound))
+
+	r, err := client.RemoveOutput("HEADLESS-2")
+	assert.NoError(t, err)
+	assert.Equal(t, r, "ok")
+
+	requests := s.Requests()
+	assert.Equal(t, requests[len(requests)-1].Raw, "output remove HEADLESS-2")
+
+	_, err = client.RemoveOutput("foo")
+	assert.True(t, errors.Is(err, ErrValidation))
+
+	_, err = client.RemoveOutput("")
+	assert.True(t, errors.Is(err, ErrEmptyRequest))
+}
+
+func TestNotifyIcon(t *testing.T) {
+	var i NotifyIcon
+	assert.Equal(t, i, NotifyIconNone)
+	assert.Equal(t, i.String(), "none")
+	assert.Equal(t, NotifyIconWarning.String(), "warning")
+	assert.Equal(t, NotifyIconOk.String(), "ok")
+	assert.Equal(t, NotifyIcon(42).String(), "NotifyIcon(42)")
+}
+
+func TestFakeNotify(t *testing.T) {
+	client, s := newFakeClient(t)
+	s.HandleResponse("notify", "ok")
+	s.HandleResponse("dismissnotify", "ok")
+	s.HandleResponse("seterror", "ok")
+
+	_, err := client.Notify(Notification{
+		Icon:     NotifyIconInfo,
+		Duration: 2 * time.Second,
+		Color:    Color{0x33, 0xcc, 0xff, 0xee},
+		FontSize: 20,
+		Message:  "Hello world",
+	})
+	assert.NoError(t, err)
+	_, err = client.Notify(Notification{Message: "Hello"})
+	assert.NoError(t, err)
+	_, err = client.DismissNotify(0)
+	assert.NoError(t, err)
+	_, err = client.DismissNotify(2)
+	assert.NoError(t, err)
+	_, err = client.SetError(Color{R: 0xff, A: 0xff}, "Something is wrong")
+	assert.NoError(t, err)
+	_, err = client.DisableError()
+	assert.NoError(t, err)
+
+	var raw []string
+	for _, req := range s.Requests() {
+		raw = append(raw, req.Raw)
+	}
+
+	assert.DeepEqual(t, raw, []string{
+		"notify 1 2000 rgba(33ccffee) fontsize:20 Hello world",
+		"notify -1 5000 0 Hello",
+		"dismissnotify -1",
+		"dismissnotify 2",
+		"seterror rgba(ff0000ff) Something is wrong",
+		"seterror disable",
+	})
+}
+
+func TestFakeClientForInstance(t *testing.T) {
+	s := hyprlandtest.NewServer(t)
+	s.HandleResponse("splash", "Hello")
+	t.Setenv("XDG_RUNTIME_DIR", s.Instance.RuntimeDir)
+	t.Setenv("HYPRLAND_INSTANCE_SIGNATURE", "")
+
+	instances, err := helpers.Instances()
+	assert.NoError(t, err)
+	assert.Equal(t, len(instances), 1)
+	assert.Equal(t, instances[0].WaylandDisplay, hyprlandtest.WaylandDisplay)
+	assert.True(t, instances[0].Alive)
+
+	splash, err := NewClientForInstance(instances[0]).Splash()
+	assert.NoError(t, err)
+	assert.Equal(t, splash, "Hello")
+
+	// MustClient falls back to the running instance
+	splash, err = MustClient().Splash()
+	assert.NoError(t, err)
+	assert.Equal(t, splash, "Hello")
+}
+
 func TestRawRequest(t *testing.T) {
 	testCommand(t, func() (RawResponse, error) {
 		return c.RawRequest([]byte("splash"))
@@ -346,6 +1238,20 @@ func TestDispatch(t *testing.T) {
 	})
 }
 
+func TestDispatchCommands(t *testing.T) {
+	testCommandRs(t, func() ([]Response, error) {
+		return c.DispatchCommands(
+			dispatcher.Exec("kitty sh -c 'echo Testing hyprland-go && sleep 1 && exit 0'"),
+		)
+	})
+}
+
+func TestClientSelector(t *testing.T) {
+	w := Window{Client{Address: "0x5651d5da1b10"}}
+	assert.Equal(t, w.Selector(), dispatcher.WindowAddress("0x5651d5da1b10"))
+	assert.Equal(t, w.Selector().String(), "address:0x5651d5da1b10")
+}
+
 func TestGetOption(t *testing.T) {
 	tests := []struct{ option string }{
 		{"general:border_size"},
@@ -436,5 +1342,9 @@ func TestVersion(t *testing.T) {
 		// in NixOS VM test that we are declaring as compatible
 		v, _ := c.Version()
 		assert.Equal(t, v.Tag, "v"+HYPRLAND_VERSION)
+
+		sv, err := c.HyprlandVersion()
+		assert.NoError(t, err)
+		assert.Equal(t, sv.String(), HYPRLAND_VERSION)
 	}
 }
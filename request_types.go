@@ -1,8 +1,14 @@
 package hyprland
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net"
+	"sync"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go/dispatcher"
 )
 
 // Indicates the version where the structs are up-to-date.
@@ -19,42 +25,306 @@ type Response string
 
 // RequestClient is the main struct from hyprland-go.
 type RequestClient struct {
-	conn *net.UnixAddr
+	conn    *net.UnixAddr
+	timeout time.Duration
+	strict  bool
+
+	// Cached result of RequestClient.Version, see RequestClient.HyprlandVersion
+	versionMu sync.Mutex
+	version   *Version
 }
 
+// Batch is a builder for batch requests mixing different commands, e.g.:
+// 'NewBatch().Dispatch("exec kitty").Keyword("general:border_size 1")', that
+// can be run with [RequestClient.DoBatch].
+type Batch struct {
+	cmds []batchCommand
+	// Errors while building the batch, returned by RequestClient.DoBatch
+	err error
+}
+
+// BatchResponse is the response for a single command from a [Batch].
+type BatchResponse struct {
+	// Command that produced the response, e.g.: "dispatch exec kitty".
+	Command  string
+	Response Response
+}
+
+// ClientOption is used to customise a [RequestClient] during its creation,
+// see [NewClient].
+type ClientOption func(*RequestClient)
+
 // ErrValidation is used to return errors from response validation. In some
 // cases you may want to ignore those errors, in this case you can use
 // [errors.Is] to compare the errors returned with this type.
 var ErrValidation = errors.New("validation error")
 
+// ErrUnsupported is returned when the running Hyprland version does not
+// support a [Capability]. Use [errors.As] with [UnsupportedError] to get more
+// details.
+var ErrUnsupported = errors.New("unsupported by Hyprland version")
+
+// SemVer is a parsed Hyprland version, e.g.: 'v0.47.2' or
+// 'v0.47.2-35-gdeadbeef' for development builds, see
+// [RequestClient.HyprlandVersion].
+type SemVer struct {
+	Major, Minor, Patch int
+	// Commits after the tag, for development builds.
+	Commits int
+}
+
+// VersionFlag is a build flag reported by Hyprland, see [Version.HasFlag].
+type VersionFlag string
+
+// Capability is a feature that requires a minimum Hyprland version, see
+// [RequestClient.Supports].
+type Capability struct {
+	// Name of the capability, used in error messages.
+	Name string
+	// Minimum Hyprland version that supports the capability.
+	Since SemVer
+}
+
+// UnsupportedError is returned when the running Hyprland version does not
+// support a [Capability]. It wraps [ErrUnsupported].
+type UnsupportedError struct {
+	Capability Capability
+	// Version of the running Hyprland.
+	Version SemVer
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf(
+		"%s: %s requires Hyprland >= %s, running: %s",
+		ErrUnsupported,
+		e.Capability.Name,
+		e.Capability.Since,
+		e.Version,
+	)
+}
+
+func (e *UnsupportedError) Unwrap() error {
+	return ErrUnsupported
+}
+
+// ErrorReason is the classified reason of a [ValidationError].
+type ErrorReason int
+
+const (
+	// The response could not be classified, see [ValidationError.Response].
+	ReasonUnknown ErrorReason = iota
+	// The dispatcher does not exist, e.g.: 'dispatch foo'.
+	ReasonUnknownDispatcher
+	// The arguments are invalid, e.g.: 'dispatch movefocus x'.
+	ReasonInvalidArgument
+	// The config option does not exist, e.g.: 'keyword foo:bar 1'.
+	ReasonUnknownOption
+	// No window matched the window selector, e.g.: 'dispatch focuswindow
+	// class:foo'.
+	ReasonNoWindowMatched
+	// The window property does not exist, e.g.: 'setprop address:0x1 foo 1'.
+	ReasonUnknownProp
+	// The plugin path does not exist or is not a shared library, e.g.:
+	// 'plugin load /tmp/foo.so'.
+	ReasonInvalidPluginPath
+	// The plugin was found but failed to initialise, e.g.: it was built
+	// against a different Hyprland version.
+	ReasonPluginInitFailed
+)
+
+func (r ErrorReason) String() string {
+	switch r {
+	case ReasonUnknownDispatcher:
+		return "unknown dispatcher"
+	case ReasonInvalidArgument:
+		return "invalid argument"
+	case ReasonUnknownOption:
+		return "unknown option"
+	case ReasonNoWindowMatched:
+		return "no window matched"
+	case ReasonUnknownProp:
+		return "unknown prop"
+	case ReasonInvalidPluginPath:
+		return "invalid plugin path"
+	case ReasonPluginInitFailed:
+		return "plugin init failed"
+	}
+
+	return "unknown"
+}
+
+// ValidationError is returned when Hyprland returns a non-ok response for a
+// command, e.g.: from [RequestClient.Dispatch] and [RequestClient.Keyword].
+// Use [errors.As] to get it. In batch mode, one error is returned for each
+// failed command, joined with [errors.Join].
+// It wraps [ErrValidation], so [errors.Is] can still be used.
+type ValidationError struct {
+	// Index of the command in the batch, 0 if not in batch mode.
+	Index int
+	// Param that caused the error, e.g.: "exec kitty". Empty for commands
+	// without parameters.
+	Param string
+	// Response returned by Hyprland.
+	Response Response
+	// Reason of the error, classified from the response.
+	Reason ErrorReason
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf(
+		"%s: non-ok response from param: %s, response: %s, reason: %s",
+		ErrValidation,
+		e.Param,
+		e.Response,
+		e.Reason,
+	)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrValidation
+}
+
+// Color is a RGBA color as used by Hyprland, e.g.: 'rgba(33ccffee)', see
+// [ParseColor].
+type Color struct {
+	R, G, B, A uint8
+}
+
+// Window properties by their kind, see [RequestClient.SetProp] and the
+// GetProp methods, e.g.: [RequestClient.GetPropFloat].
+type (
+	BoolProp  string
+	IntProp   string
+	FloatProp string
+	ColorProp string
+)
+
+// Window properties supported by this library. Other properties can still be
+// used by converting their names, e.g.: 'hyprland.BoolProp("noanim")'.
+const (
+	// Window opacity, from 0.0 to 1.0.
+	PropAlpha FloatProp = "alpha"
+	// Window opacity when inactive, from 0.0 to 1.0.
+	PropAlphaInactive FloatProp = "alphainactive"
+	// Border color when active.
+	PropActiveBorderColor ColorProp = "activebordercolor"
+	// Border color when inactive.
+	PropInactiveBorderColor ColorProp = "inactivebordercolor"
+	// Corner rounding, in pixels.
+	PropRounding IntProp = "rounding"
+	// Disable blur for the window.
+	PropForceNoBlur BoolProp = "forcenoblur"
+	// Disable focus for the window.
+	PropNoFocus BoolProp = "nofocus"
+)
+
+// PropSetting is a value for a window property, see [RequestClient.SetProp].
+// Create it with the Value method from the property, e.g.:
+// 'hyprland.PropAlpha.Value(0.8)'.
+type PropSetting struct {
+	// Name of the property, e.g.: "alpha".
+	Name string
+	// Value formatted as expected by Hyprland, e.g.: "0.8".
+	Value string
+	// Lock the property, so it is not changed by window rules or
+	// Hyprland itself (e.g.: when the window gets inactive).
+	Lock bool
+}
+
+// NotifyIcon is the icon of a [Notification]. The zero value is
+// [NotifyIconNone], so notifications have no icon by default.
+type NotifyIcon int
+
+// The values are offset by one from the ones used by Hyprland, where -1 means
+// no icon, so the zero value is NotifyIconNone.
+const (
+	NotifyIconNone NotifyIcon = iota
+	NotifyIconWarning
+	NotifyIconInfo
+	NotifyIconHint
+	NotifyIconError
+	NotifyIconConfused
+	NotifyIconOk
+)
+
+// Notification is shown by Hyprland, see [RequestClient.Notify].
+type Notification struct {
+	Icon NotifyIcon
+	// How long the notification is shown. Defaults to
+	// [DefaultNotifyDuration].
+	Duration time.Duration
+	// Color of the notification. Defaults to the color of the icon.
+	Color Color
+	// Font size, in points. Defaults to Hyprland's default, i.e.: 13.
+	FontSize int
+	Message  string
+}
+
+// OutputBackend is the backend used to create an output, see
+// [RequestClient.CreateOutput].
+type OutputBackend string
+
+const (
+	// Virtual output without a physical display, e.g.: to stream it.
+	OutputHeadless OutputBackend = "headless"
+	// Output shown as a window, when Hyprland is nested in another Wayland
+	// compositor.
+	OutputWayland OutputBackend = "wayland"
+	// Choose the backend automatically.
+	OutputAuto OutputBackend = "auto"
+)
+
+// MonitorRule is a rule for the monitor keyword, e.g.:
+// 'monitor HEADLESS-2,1920x1080@60,1920x0,1'. Use it with
+// [RequestClient.Keyword], e.g.: 'c.Keyword(rule.String())'.
+type MonitorRule struct {
+	// Name of the monitor, e.g.: "DP-1".
+	Name string
+	// Resolution, e.g.: "1920x1080@60". Defaults to "preferred".
+	Resolution string
+	// Position, e.g.: "1920x0". Defaults to "auto".
+	Position string
+	// Scale, e.g.: 1.5. Defaults to "auto".
+	Scale float64
+}
+
 // Unmarshal structs for requests.
 // Try to keep struct fields in the same order as the output for `hyprctl -j`
 // for sanity.
+// Fields unknown by this library (e.g.: added in a newer Hyprland version)
+// are kept in the Extra field of each struct, see [WithStrictDecoding]. Since
+// Extra is a map, the structs are not comparable with ==, use e.g.: their Id or
+// Address instead. Small value types (i.e.: [CursorPos] and [WorkspaceType])
+// have no Extra field so they are still comparable, and the unknown fields
+// from a nested WorkspaceType are kept in the Extra field of its parent, e.g.:
+// "workspace.newField" in [Client].
 
 type Animation struct {
-	Name       string  `json:"name"`
-	Overridden bool    `json:"overridden"`
-	Bezier     string  `json:"bezier"`
-	Enabled    bool    `json:"enabled"`
-	Speed      float64 `json:"speed"`
-	Style      string  `json:"style"`
+	Name       string                     `json:"name"`
+	Overridden bool                       `json:"overridden"`
+	Bezier     string                     `json:"bezier"`
+	Enabled    bool                       `json:"enabled"`
+	Speed      float64                    `json:"speed"`
+	Style      string                     `json:"style"`
+	Extra      map[string]json.RawMessage `json:"-"`
 }
 
 type Bind struct {
-	Locked         bool   `json:"locked"`
-	Mouse          bool   `json:"mouse"`
-	Release        bool   `json:"release"`
-	Repeat         bool   `json:"repeat"`
-	NonConsuming   bool   `json:"non_consuming"`
-	HasDescription bool   `json:"has_description"`
-	ModMask        int    `json:"modmask"`
-	SubMap         string `json:"submap"`
-	Key            string `json:"key"`
-	KeyCode        int    `json:"keycode"`
-	CatchAll       bool   `json:"catch_all"`
-	Description    string `json:"description"`
-	Dispatcher     string `json:"dispatcher"`
-	Arg            string `json:"arg"`
+	Locked         bool                       `json:"locked"`
+	Mouse          bool                       `json:"mouse"`
+	Release        bool                       `json:"release"`
+	Repeat         bool                       `json:"repeat"`
+	NonConsuming   bool                       `json:"non_consuming"`
+	HasDescription bool                       `json:"has_description"`
+	ModMask        int                        `json:"modmask"`
+	SubMap         string                     `json:"submap"`
+	Key            string                     `json:"key"`
+	KeyCode        int                        `json:"keycode"`
+	CatchAll       bool                       `json:"catch_all"`
+	Description    string                     `json:"description"`
+	Dispatcher     string                     `json:"dispatcher"`
+	Arg            string                     `json:"arg"`
+	Extra          map[string]json.RawMessage `json:"-"`
 }
 
 type FullscreenState int
@@ -67,28 +337,35 @@ const (
 )
 
 type Client struct {
-	Address          string          `json:"address"`
-	Mapped           bool            `json:"mapped"`
-	Hidden           bool            `json:"hidden"`
-	At               []int           `json:"at"`
-	Size             []int           `json:"size"`
-	Workspace        WorkspaceType   `json:"workspace"`
-	Floating         bool            `json:"floating"`
-	Pseudo           bool            `json:"pseudo"`
-	Monitor          int             `json:"monitor"`
-	Class            string          `json:"class"`
-	Title            string          `json:"title"`
-	InitialClass     string          `json:"initialClass"`
-	InitialTitle     string          `json:"initialTitle"`
-	Pid              int             `json:"pid"`
-	Xwayland         bool            `json:"xwayland"`
-	Pinned           bool            `json:"pinned"`
-	Fullscreen       FullscreenState `json:"fullscreen"`
-	FullscreenClient FullscreenState `json:"fullscreenClient"`
-	Grouped          []string        `json:"grouped"`
-	Tags             []string        `json:"tags"`
-	Swallowing       string          `json:"swallowing"`
-	FocusHistoryId   int             `json:"focusHistoryID"`
+	Address          string                     `json:"address"`
+	Mapped           bool                       `json:"mapped"`
+	Hidden           bool                       `json:"hidden"`
+	At               []int                      `json:"at"`
+	Size             []int                      `json:"size"`
+	Workspace        WorkspaceType              `json:"workspace"`
+	Floating         bool                       `json:"floating"`
+	Pseudo           bool                       `json:"pseudo"`
+	Monitor          int                        `json:"monitor"`
+	Class            string                     `json:"class"`
+	Title            string                     `json:"title"`
+	InitialClass     string                     `json:"initialClass"`
+	InitialTitle     string                     `json:"initialTitle"`
+	Pid              int                        `json:"pid"`
+	Xwayland         bool                       `json:"xwayland"`
+	Pinned           bool                       `json:"pinned"`
+	Fullscreen       FullscreenState            `json:"fullscreen"`
+	FullscreenClient FullscreenState            `json:"fullscreenClient"`
+	Grouped          []string                   `json:"grouped"`
+	Tags             []string                   `json:"tags"`
+	Swallowing       string                     `json:"swallowing"`
+	FocusHistoryId   int                        `json:"focusHistoryID"`
+	Extra            map[string]json.RawMessage `json:"-"`
+}
+
+// Selector returns a [dispatcher.WindowSelector] matching this client by its
+// address, that can be used in dispatchers and window rules.
+func (c Client) Selector() dispatcher.WindowSelector {
+	return dispatcher.WindowAddress(c.Address)
 }
 
 type ConfigError string
@@ -99,33 +376,44 @@ type CursorPos struct {
 }
 
 type Decoration struct {
-	DecorationName string `json:"decorationName"`
-	Priority       int    `json:"priority"`
+	DecorationName string                     `json:"decorationName"`
+	Priority       int                        `json:"priority"`
+	Extra          map[string]json.RawMessage `json:"-"`
 }
 
 type Devices struct {
-	Mice []struct {
-		Address      string  `json:"address"`
-		Name         string  `json:"name"`
-		DefaultSpeed float64 `json:"defaultSpeed"`
-	} `json:"mice"`
-	Keyboards []struct {
-		Address      string `json:"address"`
-		Name         string `json:"name"`
-		Rules        string `json:"rules"`
-		Model        string `json:"model"`
-		Layout       string `json:"layout"`
-		Variant      string `json:"variant"`
-		Options      string `json:"options"`
-		ActiveKeymap string `json:"active_keymap"`
-		Main         bool   `json:"main"`
-	} `json:"keyboards"`
-	Tablets  []interface{} `json:"tablets"` // TODO: need a tablet to test
-	Touch    []interface{} `json:"touch"`   // TODO: need a touchscreen to test
-	Switches []struct {
-		Address string `json:"address"`
-		Name    string `json:"name"`
-	} `json:"switches"`
+	Mice      []Mouse                    `json:"mice"`
+	Keyboards []Keyboard                 `json:"keyboards"`
+	Tablets   []interface{}              `json:"tablets"` // TODO: need a tablet to test
+	Touch     []interface{}              `json:"touch"`   // TODO: need a touchscreen to test
+	Switches  []Switch                   `json:"switches"`
+	Extra     map[string]json.RawMessage `json:"-"`
+}
+
+type Mouse struct {
+	Address      string                     `json:"address"`
+	Name         string                     `json:"name"`
+	DefaultSpeed float64                    `json:"defaultSpeed"`
+	Extra        map[string]json.RawMessage `json:"-"`
+}
+
+type Keyboard struct {
+	Address      string                     `json:"address"`
+	Name         string                     `json:"name"`
+	Rules        string                     `json:"rules"`
+	Model        string                     `json:"model"`
+	Layout       string                     `json:"layout"`
+	Variant      string                     `json:"variant"`
+	Options      string                     `json:"options"`
+	ActiveKeymap string                     `json:"active_keymap"`
+	Main         bool                       `json:"main"`
+	Extra        map[string]json.RawMessage `json:"-"`
+}
+
+type Switch struct {
+	Address string                     `json:"address"`
+	Name    string                     `json:"name"`
+	Extra   map[string]json.RawMessage `json:"-"`
 }
 
 type Output string
@@ -133,59 +421,82 @@ type Output string
 type Layers map[Output]Layer
 
 type Layer struct {
-	Levels map[int][]LayerField `json:"levels"`
+	Levels map[int][]LayerField       `json:"levels"`
+	Extra  map[string]json.RawMessage `json:"-"`
 }
 
 type LayerField struct {
-	Address   string `json:"address"`
-	X         int    `json:"x"`
-	Y         int    `json:"y"`
-	W         int    `json:"w"`
-	H         int    `json:"h"`
-	Namespace string `json:"namespace"`
+	Address   string                     `json:"address"`
+	X         int                        `json:"x"`
+	Y         int                        `json:"y"`
+	W         int                        `json:"w"`
+	H         int                        `json:"h"`
+	Namespace string                     `json:"namespace"`
+	Extra     map[string]json.RawMessage `json:"-"`
 }
 
 type Monitor struct {
-	Id               int           `json:"id"`
-	Name             string        `json:"name"`
-	Description      string        `json:"description"`
-	Make             string        `json:"make"`
-	Model            string        `json:"model"`
-	Serial           string        `json:"serial"`
-	Width            int           `json:"width"`
-	Height           int           `json:"height"`
-	RefreshRate      float64       `json:"refreshRate"`
-	X                int           `json:"x"`
-	Y                int           `json:"y"`
-	ActiveWorkspace  WorkspaceType `json:"activeWorkspace"`
-	SpecialWorkspace WorkspaceType `json:"specialWorkspace"`
-	Reserved         []int         `json:"reserved"`
-	Scale            float64       `json:"scale"`
-	Transform        int           `json:"transform"`
-	Focused          bool          `json:"focused"`
-	DpmsStatus       bool          `json:"dpmsStatus"`
-	Vrr              bool          `json:"vrr"`
-	ActivelyTearing  bool          `json:"activelyTearing"`
-	CurrentFormat    string        `json:"currentFormat"`
-	AvailableModes   []string      `json:"availableModes"`
+	Id               int                        `json:"id"`
+	Name             string                     `json:"name"`
+	Description      string                     `json:"description"`
+	Make             string                     `json:"make"`
+	Model            string                     `json:"model"`
+	Serial           string                     `json:"serial"`
+	Width            int                        `json:"width"`
+	Height           int                        `json:"height"`
+	RefreshRate      float64                    `json:"refreshRate"`
+	X                int                        `json:"x"`
+	Y                int                        `json:"y"`
+	ActiveWorkspace  WorkspaceType              `json:"activeWorkspace"`
+	SpecialWorkspace WorkspaceType              `json:"specialWorkspace"`
+	Reserved         []int                      `json:"reserved"`
+	Scale            float64                    `json:"scale"`
+	Transform        int                        `json:"transform"`
+	Focused          bool                       `json:"focused"`
+	DpmsStatus       bool                       `json:"dpmsStatus"`
+	Vrr              bool                       `json:"vrr"`
+	ActivelyTearing  bool                       `json:"activelyTearing"`
+	CurrentFormat    string                     `json:"currentFormat"`
+	AvailableModes   []string                   `json:"availableModes"`
+	Extra            map[string]json.RawMessage `json:"-"`
 }
 
 type Option struct {
-	Option string  `json:"option"`
-	Int    int     `json:"int"`
-	Float  float64 `json:"float"`
-	Set    bool    `json:"set"`
+	Option string                     `json:"option"`
+	Int    int                        `json:"int"`
+	Float  float64                    `json:"float"`
+	Set    bool                       `json:"set"`
+	Extra  map[string]json.RawMessage `json:"-"`
+}
+
+type Plugin struct {
+	Name        string                     `json:"name"`
+	Author      string                     `json:"author"`
+	Handle      string                     `json:"handle"`
+	Version     string                     `json:"version"`
+	Description string                     `json:"description"`
+	Extra       map[string]json.RawMessage `json:"-"`
+}
+
+// Snapshot is the result of [RequestClient.Snapshot].
+type Snapshot struct {
+	Clients         []Client
+	Workspaces      []Workspace
+	Monitors        []Monitor
+	ActiveWindow    Window
+	ActiveWorkspace Workspace
 }
 
 type Version struct {
-	Branch        string   `json:"branch"`
-	Commit        string   `json:"commit"`
-	Dirty         bool     `json:"dirty"`
-	CommitMessage string   `json:"commit_message"`
-	CommitDate    string   `json:"commit_date"`
-	Tag           string   `json:"tag"`
-	Commits       string   `json:"commits"`
-	Flags         []string `json:"flags"`
+	Branch        string                     `json:"branch"`
+	Commit        string                     `json:"commit"`
+	Dirty         bool                       `json:"dirty"`
+	CommitMessage string                     `json:"commit_message"`
+	CommitDate    string                     `json:"commit_date"`
+	Tag           string                     `json:"tag"`
+	Commits       string                     `json:"commits"`
+	Flags         []string                   `json:"flags"`
+	Extra         map[string]json.RawMessage `json:"-"`
 }
 
 type Window struct {
@@ -194,12 +505,13 @@ type Window struct {
 
 type Workspace struct {
 	WorkspaceType
-	Monitor         string `json:"monitor"`
-	MonitorID       int    `json:"monitorID"`
-	Windows         int    `json:"windows"`
-	HasFullScreen   bool   `json:"hasfullscreen"`
-	LastWindow      string `json:"lastwindow"`
-	LastWindowTitle string `json:"lastwindowtitle"`
+	Monitor         string                     `json:"monitor"`
+	MonitorID       int                        `json:"monitorID"`
+	Windows         int                        `json:"windows"`
+	HasFullScreen   bool                       `json:"hasfullscreen"`
+	LastWindow      string                     `json:"lastwindow"`
+	LastWindowTitle string                     `json:"lastwindowtitle"`
+	Extra           map[string]json.RawMessage `json:"-"`
 }
 
 type WorkspaceType struct {
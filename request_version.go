@@ -0,0 +1,243 @@
+package hyprland
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Returned when the version tag can not be parsed, e.g.: Hyprland was built
+// without git information.
+var ErrInvalidVersion = errors.New("invalid version")
+
+// Build flags reported by Hyprland in [Version], e.g.: for builds without
+// XWayland support.
+const (
+	FlagDebug          VersionFlag = "debug"
+	FlagNoXWayland     VersionFlag = "no xwayland"
+	FlagLegacyRenderer VersionFlag = "legacy renderer"
+	FlagNoSystemd      VersionFlag = "no systemd"
+)
+
+// Capabilities that depend on the Hyprland version. Keep in mind that those
+// are the versions where the features were released, it may be off for
+// development builds.
+var (
+	// Events with workspace IDs, e.g.: workspacev2 and createworkspacev2.
+	CapWorkspaceV2Events = Capability{"workspace v2 events", SemVer{0, 34, 0, 0}}
+	// Fullscreen state as an int enum in [Client], see [FullscreenState].
+	CapFullscreenState = Capability{"fullscreen state", SemVer{0, 42, 0, 0}}
+	// Custom events with the 'event' dispatcher, see
+	// [RequestClient.CustomEvent].
+	CapCustomEvent = Capability{"custom event", SemVer{0, 39, 0, 0}}
+	// Reading window properties, see [RequestClient.GetPropFloat].
+	CapGetProp = Capability{"getprop", SemVer{0, 46, 0, 0}}
+)
+
+// ParseSemVer parses a version tag from Hyprland, e.g.: 'v0.47.2' or
+// 'v0.47.2-35-gdeadbeef' (from 'git describe').
+func ParseSemVer(tag string) (v SemVer, err error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "v")
+	version, describe, _ := strings.Cut(tag, "-")
+
+	parts := strings.Split(version, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("%w: %q", ErrInvalidVersion, tag)
+	}
+
+	nums := make([]int, len(parts))
+	for i, p := range parts {
+		if nums[i], err = strconv.Atoi(p); err != nil {
+			return v, fmt.Errorf("%w: %q: %w", ErrInvalidVersion, tag, err)
+		}
+	}
+
+	v = SemVer{Major: nums[0], Minor: nums[1], Patch: nums[2]}
+
+	// e.g.: 35-gdeadbeef, anything else (like -rc1) is ignored
+	if commits, _, found := strings.Cut(describe, "-"); found {
+		v.Commits, _ = strconv.Atoi(commits)
+	}
+
+	return v, nil
+}
+
+// Returns the version in the same format as HYPRLAND_VERSION, e.g.: "0.47.2",
+// or "0.47.2+35" for development builds.
+func (v SemVer) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Commits > 0 {
+		s += fmt.Sprintf("+%d", v.Commits)
+	}
+
+	return s
+}
+
+// Compare returns -1 if v < o, 0 if v == o and +1 if v > o.
+func (v SemVer) Compare(o SemVer) int {
+	for _, d := range []int{v.Major - o.Major, v.Minor - o.Minor, v.Patch - o.Patch, v.Commits - o.Commits} {
+		switch {
+		case d < 0:
+			return -1
+		case d > 0:
+			return 1
+		}
+	}
+
+	return 0
+}
+
+// AtLeast returns true if v >= o.
+func (v SemVer) AtLeast(o SemVer) bool {
+	return v.Compare(o) >= 0
+}
+
+// SemVer parses the Tag from the version, see [ParseSemVer]. The commits
+// after the tag only come from the tag itself, since Commits is the total
+// number of commits in the repository.
+func (v Version) SemVer() (SemVer, error) {
+	return ParseSemVer(v.Tag)
+}
+
+// HasFlag returns true if Hyprland was built with the flag, e.g.:
+// [FlagNoXWayland].
+func (v Version) HasFlag(flag VersionFlag) bool {
+	return slices.Contains(v.Flags, string(flag))
+}
+
+// HyprlandVersion returns the parsed version of the running Hyprland, using
+// [RequestClient.Version]. The result is cached after the first successful
+// call.
+func (c *RequestClient) HyprlandVersion() (v SemVer, err error) {
+	return c.HyprlandVersionWithContext(context.Background())
+}
+
+// Same as [RequestClient.HyprlandVersion], but accepts a [context.Context]
+// that can be used to cancel the request or set a deadline.
+func (c *RequestClient) HyprlandVersionWithContext(ctx context.Context) (v SemVer, err error) {
+	version, err := c.cachedVersion(ctx)
+	if err != nil {
+		return v, err
+	}
+
+	return version.SemVer()
+}
+
+// HasFlag returns true if the running Hyprland was built with the flag, e.g.:
+// [FlagDebug]. Uses the same cache as [RequestClient.HyprlandVersion].
+func (c *RequestClient) HasFlag(flag VersionFlag) (bool, error) {
+	return c.HasFlagWithContext(context.Background(), flag)
+}
+
+// Same as [RequestClient.HasFlag], but accepts a [context.Context] that can
+// be used to cancel the request or set a deadline.
+func (c *RequestClient) HasFlagWithContext(ctx context.Context, flag VersionFlag) (bool, error) {
+	version, err := c.cachedVersion(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return version.HasFlag(flag), nil
+}
+
+// Returns the result of [RequestClient.Version], cached after the first
+// successful call. The cache is dropped once Hyprland can not be reached (e.g.:
+// it was restarted after an upgrade) and before reporting an unsupported
+// capability, see invalidateVersion.
+func (c *RequestClient) cachedVersion(ctx context.Context) (v Version, err error) {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	if c.version != nil {
+		return *c.version, nil
+	}
+
+	v, err = c.VersionWithContext(ctx)
+	// Unknown fields in strict mode should not stop the version checks
+	if err != nil && !errors.Is(err, ErrUnknownFields) {
+		return v, err
+	}
+
+	c.version = &v
+
+	return v, nil
+}
+
+// Drop the cached version, so it is fetched again in the next check.
+func (c *RequestClient) invalidateVersion() {
+	c.versionMu.Lock()
+	defer c.versionMu.Unlock()
+
+	c.version = nil
+}
+
+// Supports returns true if the running Hyprland supports the capability.
+// Development builds without a valid version tag are assumed to support all
+// capabilities.
+func (c *RequestClient) Supports(capability Capability) (bool, error) {
+	return c.SupportsWithContext(context.Background(), capability)
+}
+
+// Same as [RequestClient.Supports], but accepts a [context.Context] that can
+// be used to cancel the request or set a deadline.
+func (c *RequestClient) SupportsWithContext(ctx context.Context, capability Capability) (bool, error) {
+	err := c.require(ctx, capability)
+	if errors.Is(err, ErrUnsupported) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+// Returns an [UnsupportedError] if the running Hyprland does not support the
+// capability. Since the version may be outdated, it is fetched again before
+// returning the error.
+func (c *RequestClient) require(ctx context.Context, capability Capability) error {
+	err := c.checkVersion(ctx, capability)
+	if errors.Is(err, ErrUnsupported) {
+		c.invalidateVersion()
+
+		return c.checkVersion(ctx, capability)
+	}
+
+	return err
+}
+
+func (c *RequestClient) checkVersion(ctx context.Context, capability Capability) error {
+	v, err := c.HyprlandVersionWithContext(ctx)
+	if errors.Is(err, ErrInvalidVersion) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("error while checking version: %w", err)
+	}
+
+	if !v.AtLeast(capability.Since) {
+		return &UnsupportedError{Capability: capability, Version: v}
+	}
+
+	return nil
+}
+
+// Explain an error returned by a command that depends on a capability, e.g.:
+// a JSON decode failure caused by an older Hyprland version. Returns an
+// [UnsupportedError] joined with err if the capability is not supported, or
+// err otherwise. The version is only checked in case of errors, so commands
+// that work in all versions do not need an extra request.
+func (c *RequestClient) explainErr(ctx context.Context, capability Capability, err error) error {
+	// Unknown fields are not caused by an older version
+	if err == nil || errors.Is(err, ErrUnknownFields) {
+		return err
+	}
+
+	var uerr *UnsupportedError
+	if errors.As(c.require(ctx, capability), &uerr) {
+		return errors.Join(uerr, err)
+	}
+
+	return err
+}
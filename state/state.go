@@ -0,0 +1,627 @@
+// Package state implements a live mirror of Hyprland's state, i.e.: windows,
+// workspaces and monitors, that is initialised with a snapshot from
+// hyprland.RequestClient and kept in sync using events from
+// event.EventClient. This avoids polling Hyprland for tools that need to
+// query the state frequently.
+package state
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/event"
+)
+
+const (
+	// Default interval between resyncs, see [WithResyncInterval].
+	DefaultResyncInterval = time.Minute
+
+	// Maximum times to retry a snapshot that was taken while events were
+	// being applied.
+	maxSyncRetries = 3
+	// Size of the buffer of channels returned by [State.Watch].
+	watchBufSize = 64
+)
+
+// Events used to keep the state in sync.
+var events = []event.EventType{
+	event.EventOpenWindow,
+	event.EventCloseWindow,
+	event.EventMoveWindow,
+	event.EventActiveWindowV2,
+	event.EventWindowTitleV2,
+	event.EventChangeFloatingMode,
+	event.EventPin,
+	event.EventFullscreen,
+	event.EventWorkspaceV2,
+	event.EventCreateWorkspaceV2,
+	event.EventDestroyWorkspaceV2,
+	event.EventMoveWorkspaceV2,
+	event.EventRenameWorkspace,
+	event.EventFocusedMonitorV2,
+	event.EventMonitorAdded,
+	event.EventMonitorRemoved,
+}
+
+// Initiate a new state.
+// Receives a client used to take snapshots and an events client used to
+// receive events, that can be created with event.WithReconnect so the state
+// is resynced after Hyprland restarts. The state is empty until [State.Sync]
+// or [State.Run] is called.
+// Optionally receives a list of [Option] to customise the state.
+func New(client *hyprland.RequestClient, events *event.EventClient, opts ...Option) *State {
+	s := &State{
+		client:         client,
+		events:         events,
+		resyncInterval: DefaultResyncInterval,
+		clients:        make(map[string]hyprland.Client),
+		workspaces:     make(map[int]hyprland.Workspace),
+		monitors:       make(map[string]hyprland.Monitor),
+		watchers:       make(map[chan Change]struct{}),
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// WithResyncInterval sets the interval between resyncs, that take a new
+// snapshot to correct any drift, e.g.: changes that do not emit events like
+// window sizes. An interval <= 0 disables periodic resyncs.
+func WithResyncInterval(interval time.Duration) Option {
+	return func(s *State) {
+		s.resyncInterval = interval
+	}
+}
+
+// Run takes an initial snapshot and keeps the state in sync until the
+// context is done or an error happens receiving events.
+// Events are subscribed before the snapshot is taken and buffered until it is
+// applied, so events emitted while the snapshot is taken are not lost. Since
+// some of them may already be included in the snapshot, applying an event is
+// idempotent, e.g.: opening a window that is already known only updates it.
+// The state is resynced periodically (see [WithResyncInterval]), after a
+// monitor is added and after the events client reconnects, since events
+// emitted while disconnected are lost. Errors while resyncing are ignored,
+// since the next resync will try again.
+// Uses event.EventClient.Subscribe, so the same restrictions apply.
+func (s *State) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	h := &handler{ctx: ctx, s: s, buffering: true}
+	done := make(chan error, 1)
+
+	go func() { done <- s.events.Subscribe(ctx, h, events...) }()
+
+	if err := s.Sync(ctx); err != nil {
+		cancel()
+		<-done
+
+		return err
+	}
+
+	h.replay()
+
+	if s.resyncInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(s.resyncInterval)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-ticker.C:
+					_ = s.Sync(ctx)
+				}
+			}
+		}()
+	}
+
+	return <-done
+}
+
+// Sync replaces the state with a new snapshot from Hyprland, see
+// hyprland.RequestClient.Snapshot.
+// If events are applied while the snapshot is taken, the snapshot may be
+// older than the current state, so it is retried.
+func (s *State) Sync(ctx context.Context) error {
+	for i := 0; ; i++ {
+		s.mu.RLock()
+		seq := s.seq
+		s.mu.RUnlock()
+
+		snap, err := s.client.SnapshotWithContext(ctx)
+		if err != nil {
+			return fmt.Errorf("error while taking snapshot: %w", err)
+		}
+
+		s.mu.Lock()
+		if s.seq != seq && i < maxSyncRetries {
+			s.mu.Unlock()
+
+			continue
+		}
+
+		s.replace(snap)
+		s.mu.Unlock()
+
+		s.notify(Change{})
+
+		return nil
+	}
+}
+
+// Watch returns a channel that receives a [Change] each time the state
+// changes, until the context is done. Changes are dropped if the channel
+// buffer is full, so slow watchers should read the state again instead of
+// relying in each change.
+func (s *State) Watch(ctx context.Context) <-chan Change {
+	ch := make(chan Change, watchBufSize)
+
+	s.watchersMu.Lock()
+	s.watchers[ch] = struct{}{}
+	s.watchersMu.Unlock()
+
+	context.AfterFunc(ctx, func() {
+		s.watchersMu.Lock()
+		defer s.watchersMu.Unlock()
+
+		delete(s.watchers, ch)
+		close(ch)
+	})
+
+	return ch
+}
+
+// Clients returns all windows, ordered by address.
+func (s *State) Clients() []hyprland.Client {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedValues(s.clients, func(a, b hyprland.Client) int {
+		return cmp.Compare(a.Address, b.Address)
+	})
+}
+
+// Client returns a window by its address, e.g.: "0x80e62df0". The "0x"
+// prefix is optional, so addresses from events can be used as is.
+func (s *State) Client(address string) (hyprland.Client, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.clients[normaliseAddress(address)]
+
+	return c, ok
+}
+
+// ActiveWindow returns the active window, if any.
+func (s *State) ActiveWindow() (hyprland.Window, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.clients[s.activeWindow]
+
+	return hyprland.Window{Client: c}, ok
+}
+
+// Workspaces returns all workspaces, ordered by ID.
+func (s *State) Workspaces() []hyprland.Workspace {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedValues(s.workspaces, func(a, b hyprland.Workspace) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+}
+
+// Workspace returns a workspace by its ID.
+func (s *State) Workspace(id int) (hyprland.Workspace, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	w, ok := s.workspaces[id]
+
+	return w, ok
+}
+
+// ActiveWorkspace returns the active workspace of the focused monitor, if
+// any.
+func (s *State) ActiveWorkspace() (hyprland.Workspace, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, m := range s.monitors {
+		if m.Focused {
+			w, ok := s.workspaces[m.ActiveWorkspace.Id]
+
+			return w, ok
+		}
+	}
+
+	return hyprland.Workspace{}, false
+}
+
+// Monitors returns all monitors, ordered by ID.
+func (s *State) Monitors() []hyprland.Monitor {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedValues(s.monitors, func(a, b hyprland.Monitor) int {
+		return cmp.Compare(a.Id, b.Id)
+	})
+}
+
+// Monitor returns a monitor by its name, e.g.: "DP-1".
+func (s *State) Monitor(name string) (hyprland.Monitor, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, ok := s.monitors[name]
+
+	return m, ok
+}
+
+// Replace the state with the snapshot, must be called with the lock held.
+func (s *State) replace(snap hyprland.Snapshot) {
+	clear(s.clients)
+	for _, c := range snap.Clients {
+		s.clients[c.Address] = c
+	}
+
+	clear(s.workspaces)
+	for _, w := range snap.Workspaces {
+		s.workspaces[w.Id] = w
+	}
+
+	clear(s.monitors)
+	for _, m := range snap.Monitors {
+		s.monitors[m.Name] = m
+	}
+
+	s.activeWindow = snap.ActiveWindow.Address
+}
+
+// Apply an event to the state, returning true if the state changed. Returns
+// false for events referring to unknown windows, workspaces or monitors.
+func (s *State) apply(e event.Event) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.seq++
+
+	switch e := e.(type) {
+	case event.OpenWindow:
+		address := normaliseAddress(e.Address)
+		// The window may be known already, e.g.: from a snapshot, so only
+		// the fields from the event are updated
+		c, ok := s.clients[address]
+		if ok {
+			s.updateWindowCount(c.Workspace.Id, -1)
+		} else {
+			c = hyprland.Client{Address: address}
+		}
+
+		c.Mapped = true
+		c.Class = e.Class
+		c.Title = e.Title
+
+		if w, ok := s.workspaceByName(e.WorkspaceName); ok {
+			c.Workspace = w.WorkspaceType
+			c.Monitor = w.MonitorID
+			w.Windows++
+			s.workspaces[w.Id] = w
+		} else {
+			c.Workspace = hyprland.WorkspaceType{Name: string(e.WorkspaceName)}
+		}
+
+		s.clients[c.Address] = c
+	case event.CloseWindow:
+		c, ok := s.clients[normaliseAddress(e.Address)]
+		if !ok {
+			return false
+		}
+
+		s.updateWindowCount(c.Workspace.Id, -1)
+		delete(s.clients, c.Address)
+
+		if s.activeWindow == c.Address {
+			s.activeWindow = ""
+		}
+	case event.MoveWindow:
+		c, ok := s.clients[normaliseAddress(e.Address)]
+		if !ok {
+			return false
+		}
+
+		s.updateWindowCount(c.Workspace.Id, -1)
+
+		if w, ok := s.workspaceByName(e.WorkspaceName); ok {
+			c.Workspace = w.WorkspaceType
+			c.Monitor = w.MonitorID
+			s.updateWindowCount(w.Id, 1)
+		} else {
+			c.Workspace = hyprland.WorkspaceType{Name: string(e.WorkspaceName)}
+		}
+
+		s.clients[c.Address] = c
+	case event.ActiveWindowV2:
+		address := normaliseAddress(e.Address)
+		if _, ok := s.clients[address]; !ok {
+			address = ""
+		}
+
+		s.activeWindow = address
+	case event.WindowTitleV2:
+		return s.updateClient(e.Address, func(c *hyprland.Client) {
+			c.Title = e.Title
+		})
+	case event.ChangeFloatingMode:
+		return s.updateClient(e.Address, func(c *hyprland.Client) {
+			c.Floating = e.Floating
+		})
+	case event.Pin:
+		return s.updateClient(e.Address, func(c *hyprland.Client) {
+			c.Pinned = e.Pinned
+		})
+	case event.Fullscreen:
+		// Always refers to the active window
+		return s.updateClient(s.activeWindow, func(c *hyprland.Client) {
+			c.Fullscreen = hyprland.None
+			if e {
+				c.Fullscreen = hyprland.Fullscreen
+			}
+
+			if w, ok := s.workspaces[c.Workspace.Id]; ok {
+				w.HasFullScreen = bool(e)
+				s.workspaces[w.Id] = w
+			}
+		})
+	case event.WorkspaceV2:
+		w, ok := s.workspaces[int(e.WorkspaceId)]
+		if !ok {
+			return false
+		}
+
+		m, ok := s.monitors[w.Monitor]
+		if !ok {
+			return false
+		}
+
+		m.ActiveWorkspace = w.WorkspaceType
+		s.monitors[m.Name] = m
+	case event.CreateWorkspaceV2:
+		w := hyprland.Workspace{WorkspaceType: hyprland.WorkspaceType{
+			Id:   int(e.WorkspaceId),
+			Name: string(e.WorkspaceName),
+		}}
+		// Workspaces are created in the focused monitor, it will be
+		// fixed by a moveworkspacev2 event otherwise
+		for _, m := range s.monitors {
+			if m.Focused {
+				w.Monitor = m.Name
+				w.MonitorID = m.Id
+			}
+		}
+
+		s.workspaces[w.Id] = w
+	case event.DestroyWorkspaceV2:
+		if _, ok := s.workspaces[int(e.WorkspaceId)]; !ok {
+			return false
+		}
+
+		delete(s.workspaces, int(e.WorkspaceId))
+	case event.MoveWorkspaceV2:
+		w, ok := s.workspaces[int(e.WorkspaceId)]
+		if !ok {
+			return false
+		}
+
+		m, ok := s.monitors[string(e.MonitorName)]
+		if !ok {
+			return false
+		}
+
+		w.Monitor = m.Name
+		w.MonitorID = m.Id
+		s.workspaces[w.Id] = w
+
+		for address, c := range s.clients {
+			if c.Workspace.Id == w.Id {
+				c.Monitor = m.Id
+				s.clients[address] = c
+			}
+		}
+	case event.RenameWorkspace:
+		w, ok := s.workspaces[int(e.WorkspaceId)]
+		if !ok {
+			return false
+		}
+
+		w.Name = string(e.NewName)
+		s.workspaces[w.Id] = w
+
+		for address, c := range s.clients {
+			if c.Workspace.Id == w.Id {
+				c.Workspace.Name = w.Name
+				s.clients[address] = c
+			}
+		}
+	case event.FocusedMonitorV2:
+		if _, ok := s.monitors[string(e.MonitorName)]; !ok {
+			return false
+		}
+
+		for name, m := range s.monitors {
+			m.Focused = name == string(e.MonitorName)
+			if m.Focused {
+				if w, ok := s.workspaces[int(e.WorkspaceId)]; ok {
+					m.ActiveWorkspace = w.WorkspaceType
+				}
+			}
+
+			s.monitors[name] = m
+		}
+	case event.MonitorRemoved:
+		if _, ok := s.monitors[string(e.MonitorName)]; !ok {
+			return false
+		}
+
+		delete(s.monitors, string(e.MonitorName))
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Update a client by its address, returning false if it is unknown. Must be
+// called with the lock held.
+func (s *State) updateClient(address string, f func(c *hyprland.Client)) bool {
+	c, ok := s.clients[normaliseAddress(address)]
+	if !ok {
+		return false
+	}
+
+	f(&c)
+	s.clients[c.Address] = c
+
+	return true
+}
+
+// Must be called with the lock held.
+func (s *State) updateWindowCount(id int, delta int) {
+	if w, ok := s.workspaces[id]; ok {
+		w.Windows = max(w.Windows+delta, 0)
+		s.workspaces[id] = w
+	}
+}
+
+// Must be called with the lock held.
+func (s *State) workspaceByName(name event.WorkspaceName) (hyprland.Workspace, bool) {
+	for _, w := range s.workspaces {
+		if w.Name == string(name) {
+			return w, true
+		}
+	}
+
+	return hyprland.Workspace{}, false
+}
+
+func (s *State) notify(c Change) {
+	s.watchersMu.Lock()
+	defer s.watchersMu.Unlock()
+
+	for ch := range s.watchers {
+		select {
+		case ch <- c:
+		default:
+		}
+	}
+}
+
+// Events use addresses without the "0x" prefix, while requests include it.
+func normaliseAddress(address string) string {
+	if address == "" || strings.HasPrefix(address, "0x") {
+		return address
+	}
+
+	return "0x" + address
+}
+
+func sortedValues[K comparable, V any](m map[K]V, compare func(a, b V) int) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+
+	slices.SortFunc(values, compare)
+
+	return values
+}
+
+// Implementation of event.EventHandler that applies the events to the state.
+type handler struct {
+	event.DefaultEventHandler
+
+	ctx context.Context
+	s   *State
+
+	mu sync.Mutex
+	// True while the initial snapshot is taken, see State.Run
+	buffering bool
+	buf       []event.Event
+}
+
+func (h *handler) handle(e event.Event) {
+	h.mu.Lock()
+	if h.buffering {
+		h.buf = append(h.buf, e)
+		h.mu.Unlock()
+
+		return
+	}
+	h.mu.Unlock()
+
+	h.apply(e)
+}
+
+func (h *handler) apply(e event.Event) {
+	if h.s.apply(e) {
+		h.s.notify(Change{Event: e})
+	}
+}
+
+// Stop buffering and apply the buffered events, in order.
+func (h *handler) replay() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, e := range h.buf {
+		h.apply(e)
+	}
+
+	h.buf = nil
+	h.buffering = false
+}
+
+func (h *handler) OpenWindow(e event.OpenWindow)                 { h.handle(e) }
+func (h *handler) CloseWindow(e event.CloseWindow)               { h.handle(e) }
+func (h *handler) MoveWindow(e event.MoveWindow)                 { h.handle(e) }
+func (h *handler) ActiveWindowV2(e event.ActiveWindowV2)         { h.handle(e) }
+func (h *handler) WindowTitleV2(e event.WindowTitleV2)           { h.handle(e) }
+func (h *handler) ChangeFloatingMode(e event.ChangeFloatingMode) { h.handle(e) }
+func (h *handler) Pin(e event.Pin)                               { h.handle(e) }
+func (h *handler) Fullscreen(e event.Fullscreen)                 { h.handle(e) }
+func (h *handler) WorkspaceV2(e event.WorkspaceV2)               { h.handle(e) }
+func (h *handler) CreateWorkspaceV2(e event.CreateWorkspaceV2)   { h.handle(e) }
+func (h *handler) DestroyWorkspaceV2(e event.DestroyWorkspaceV2) { h.handle(e) }
+func (h *handler) MoveWorkspaceV2(e event.MoveWorkspaceV2)       { h.handle(e) }
+func (h *handler) RenameWorkspace(e event.RenameWorkspace)       { h.handle(e) }
+func (h *handler) FocusedMonitorV2(e event.FocusedMonitorV2)     { h.handle(e) }
+
+func (h *handler) MonitorRemoved(m event.MonitorName) {
+	h.handle(event.MonitorRemoved{MonitorName: m})
+}
+
+// The event does not include the monitor information, so we need a new
+// snapshot.
+func (h *handler) MonitorAdded(event.MonitorName) {
+	_ = h.s.Sync(h.ctx)
+}
+
+func (h *handler) Disconnected(error) {}
+
+// Events emitted while disconnected are lost, so we need a new snapshot.
+func (h *handler) Reconnected() {
+	_ = h.s.Sync(h.ctx)
+}
@@ -0,0 +1,258 @@
+package state
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/event"
+	"github.com/thiagokokada/hyprland-go/hyprlandtest"
+	"github.com/thiagokokada/hyprland-go/internal/assert"
+)
+
+const (
+	clients = `[
+		{"address": "0x1", "workspace": {"id": 1, "name": "1"}, "class": "kitty", "title": "fish", "pid": 42, "floating": true, "at": [10, 20]},
+		{"address": "0x2", "workspace": {"id": 2, "name": "2"}, "class": "firefox", "title": "web"}
+	]`
+	workspaces = `[
+		{"id": 1, "name": "1", "monitor": "DP-1", "monitorID": 0, "windows": 1},
+		{"id": 2, "name": "2", "monitor": "DP-1", "monitorID": 0, "windows": 1}
+	]`
+	monitors = `[
+		{"id": 0, "name": "DP-1", "activeWorkspace": {"id": 1, "name": "1"}, "focused": true}
+	]`
+	activeWindow    = `{"address": "0x1", "workspace": {"id": 1, "name": "1"}, "class": "kitty", "title": "fish"}`
+	activeWorkspace = `{"id": 1, "name": "1", "monitor": "DP-1", "monitorID": 0, "windows": 1}`
+)
+
+func newFakeState(t *testing.T, opts ...Option) (*State, *hyprlandtest.Server, *hyprlandtest.EventServer) {
+	t.Helper()
+
+	s := hyprlandtest.NewServer(t)
+	s.HandleResponse("clients", clients)
+	s.HandleResponse("workspaces", workspaces)
+	s.HandleResponse("monitors", monitors)
+	s.HandleResponse("activewindow", activeWindow)
+	s.HandleResponse("activeworkspace", activeWorkspace)
+
+	es := s.Instance.NewEventServer(t)
+
+	ev, err := event.NewClient(es.Socket)
+	assert.NoError(t, err)
+	t.Cleanup(func() { ev.Close() })
+
+	return New(hyprland.NewClient(s.Socket), ev, opts...), s, es
+}
+
+func TestSync(t *testing.T) {
+	st, _, _ := newFakeState(t)
+
+	assert.NoError(t, st.Sync(context.Background()))
+
+	assert.Equal(t, len(st.Clients()), 2)
+	assert.Equal(t, st.Clients()[1].Class, "firefox")
+	assert.Equal(t, len(st.Workspaces()), 2)
+	assert.Equal(t, len(st.Monitors()), 1)
+
+	w, ok := st.ActiveWindow()
+	assert.True(t, ok)
+	assert.Equal(t, w.Address, "0x1")
+
+	ws, ok := st.ActiveWorkspace()
+	assert.True(t, ok)
+	assert.Equal(t, ws.Id, 1)
+}
+
+func TestApply(t *testing.T) {
+	st, _, _ := newFakeState(t)
+	assert.NoError(t, st.Sync(context.Background()))
+
+	tests := []struct {
+		event event.Event
+		want  bool
+	}{
+		{event.OpenWindow{Address: "3", Class: "mpv", Title: "video", WorkspaceName: "2"}, true},
+		{event.ActiveWindowV2{Address: "3"}, true},
+		{event.Fullscreen(true), true},
+		{event.WindowTitleV2{Address: "3", Title: "other, video"}, true},
+		{event.ChangeFloatingMode{Address: "1", Floating: true}, true},
+		{event.CloseWindow{Address: "2"}, true},
+		{event.CloseWindow{Address: "4"}, false},
+		{event.CreateWorkspaceV2{WorkspaceId: 3, WorkspaceName: "3"}, true},
+		{event.RenameWorkspace{WorkspaceId: 2, NewName: "video"}, true},
+		{event.DestroyWorkspaceV2{WorkspaceId: 3, WorkspaceName: "3"}, true},
+		{event.WorkspaceV2{WorkspaceId: 2, WorkspaceName: "video"}, true},
+		{event.MoveWorkspaceV2{WorkspaceId: 2, WorkspaceName: "video", MonitorName: "HDMI-1"}, false},
+		{event.Urgent{Address: "1"}, false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, st.apply(tt.event), tt.want)
+	}
+
+	w, ok := st.ActiveWindow()
+	assert.True(t, ok)
+	assert.Equal(t, w.Address, "0x3")
+	assert.Equal(t, w.Title, "other, video")
+	assert.Equal(t, w.Fullscreen, hyprland.Fullscreen)
+	assert.DeepEqual(t, w.Workspace, hyprland.WorkspaceType{Id: 2, Name: "video"})
+
+	c, ok := st.Client("0x1")
+	assert.True(t, ok)
+	assert.True(t, c.Floating)
+
+	_, ok = st.Client("2")
+	assert.False(t, ok)
+
+	ws, ok := st.ActiveWorkspace()
+	assert.True(t, ok)
+	assert.Equal(t, ws.Name, "video")
+	assert.Equal(t, ws.Windows, 1)
+	assert.True(t, ws.HasFullScreen)
+
+	_, ok = st.Workspace(3)
+	assert.False(t, ok)
+}
+
+func TestApplyOpenWindowKnown(t *testing.T) {
+	st, _, _ := newFakeState(t)
+	assert.NoError(t, st.Sync(context.Background()))
+
+	// e.g.: an event replayed over the snapshot in Run
+	assert.True(t, st.apply(event.OpenWindow{Address: "1", Class: "kitty", Title: "vim", WorkspaceName: "2"}))
+
+	c, ok := st.Client("0x1")
+	assert.True(t, ok)
+	assert.Equal(t, c.Title, "vim")
+	assert.Equal(t, c.Workspace.Id, 2)
+	assert.Equal(t, c.Pid, 42)
+	assert.True(t, c.Floating)
+	assert.DeepEqual(t, c.At, []int{10, 20})
+
+	ws, _ := st.Workspace(1)
+	assert.Equal(t, ws.Windows, 0)
+	ws, _ = st.Workspace(2)
+	assert.Equal(t, ws.Windows, 2)
+}
+
+func TestRun(t *testing.T) {
+	st, s, es := newFakeState(t, WithResyncInterval(100*time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := st.Watch(ctx)
+	done := make(chan error)
+
+	go func() { done <- st.Run(ctx) }()
+
+	// Initial sync
+	assert.DeepEqual(t, <-changes, Change{})
+
+	conn := es.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	go conn.Emit("closewindow", "1", "urgent", "2")
+
+	assert.DeepEqual(t, <-changes, Change{Event: event.CloseWindow{Address: "1"}})
+
+	_, ok := st.ActiveWindow()
+	assert.False(t, ok)
+
+	// Periodic resync restores the state from Hyprland
+	assert.DeepEqual(t, <-changes, Change{})
+
+	_, ok = st.ActiveWindow()
+	assert.True(t, ok)
+	assert.GreaterOrEqual(t, len(s.Requests()), 2)
+
+	cancel()
+	<-done
+
+	// Channel is closed once the context is done
+	for range changes {
+	}
+}
+
+func TestRunEventsDuringSync(t *testing.T) {
+	st, s, es := newFakeState(t)
+
+	conn := es.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	// Both events are emitted while the snapshot is taken, the first one is
+	// already included in it while the second one is not
+	s.Handle("clients", func(hyprlandtest.Command) string {
+		assert.NoError(t, conn.Emit(
+			"openwindow", "1,1,kitty,fish",
+			"openwindow", "3,1,mpv,video",
+		))
+
+		return clients
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := st.Watch(ctx)
+	done := make(chan error)
+
+	go func() { done <- st.Run(ctx) }()
+
+	// Initial sync
+	assert.DeepEqual(t, <-changes, Change{})
+
+	for c := range changes {
+		if e, ok := c.Event.(event.OpenWindow); ok && e.Address == "3" {
+			break
+		}
+	}
+
+	assert.Equal(t, len(st.Clients()), 3)
+
+	ws, ok := st.Workspace(1)
+	assert.True(t, ok)
+	assert.Equal(t, ws.Windows, 2)
+
+	cancel()
+	<-done
+}
+
+func TestRunActiveWindowEmpty(t *testing.T) {
+	st, _, es := newFakeState(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := st.Watch(ctx)
+	done := make(chan error)
+
+	go func() { done <- st.Run(ctx) }()
+
+	// Initial sync
+	assert.DeepEqual(t, <-changes, Change{})
+
+	_, ok := st.ActiveWindow()
+	assert.True(t, ok)
+
+	conn := es.Accept(time.Second)
+	if conn == nil {
+		t.Fatal("client did not connect")
+	}
+
+	// Sent when focusing an empty workspace
+	go conn.Emit("activewindowv2", "")
+
+	assert.DeepEqual(t, <-changes, Change{Event: event.ActiveWindowV2{Address: ""}})
+
+	_, ok = st.ActiveWindow()
+	assert.False(t, ok)
+
+	cancel()
+	<-done
+}
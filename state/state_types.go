@@ -0,0 +1,41 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thiagokokada/hyprland-go"
+	"github.com/thiagokokada/hyprland-go/event"
+)
+
+// State is an in-memory model of the windows, workspaces and monitors from
+// Hyprland, kept in sync by events. It is safe to be read concurrently.
+type State struct {
+	client *hyprland.RequestClient
+	events *event.EventClient
+
+	resyncInterval time.Duration
+
+	mu sync.RWMutex
+	// Incremented for each event applied, used to detect snapshots that
+	// are older than the current state
+	seq          uint64
+	clients      map[string]hyprland.Client
+	workspaces   map[int]hyprland.Workspace
+	monitors     map[string]hyprland.Monitor
+	activeWindow string
+
+	watchersMu sync.Mutex
+	watchers   map[chan Change]struct{}
+}
+
+// Option is used to customise a [State] during its creation, see [New].
+type Option func(*State)
+
+// Change is sent to the channels returned by [State.Watch] after the state
+// changes.
+type Change struct {
+	// Event that caused the change, or nil if the change was caused by a
+	// resync.
+	Event event.Event
+}